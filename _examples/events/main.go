@@ -35,12 +35,7 @@ func (h *EventHandler) Notify(world *ecs.World, evt ecs.EntityEvent) {
 	fmt.Printf("    Entity:        %+v\n", evt.Entity)
 	fmt.Printf("    Added/Removed: %+v / %+v\n", evt.AddedIDs, evt.RemovedIDs)
 	fmt.Printf("    Relation:      %+v -> %+v\n", evt.OldRelation, evt.NewRelation)
-
-	var target ecs.Entity
-	if evt.NewRelation != nil {
-		target = world.Relations().Get(evt.Entity, *evt.NewRelation)
-	}
-	fmt.Printf("    Target:        %+v -> %+v\n", evt.OldTarget, target)
+	fmt.Printf("    Target:        %+v -> %+v\n", evt.OldTarget, evt.NewTarget)
 }
 
 func main() {
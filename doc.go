@@ -6,6 +6,10 @@
 //   - Generic API -- [github.com/mlange-42/arche/generic]
 //   - Advanced filters -- [github.com/mlange-42/arche/filter]
 //   - Event listeners -- [github.com/mlange-42/arche/listener]
+//   - User-defined events -- [github.com/mlange-42/arche/events]
+//   - Recording and replay -- [github.com/mlange-42/arche/journal]
+//   - State replication -- [github.com/mlange-42/arche/replicate]
+//   - Test helpers -- [github.com/mlange-42/arche/ecstest]
 //   - Usage examples -- [github.com/mlange-42/arche/_examples]
 //
 // 🕮 Also read Arche's [User Guide]!
@@ -152,3 +152,49 @@ func TestEntitiesAlive(t *testing.T) {
 	world.RemoveEntity(entity)
 	fmt.Println(world.Alive(entity)) // prints false
 }
+
+func TestEntitiesNames(t *testing.T) {
+	world := ecs.NewWorld()
+
+	entity := world.NewEntity()
+	world.SetName(entity, "player")
+
+	player, ok := world.Lookup("player")
+	fmt.Println(player == entity, ok) // prints true true
+	fmt.Println(world.Name(entity))   // prints player
+
+	world.RemoveEntity(entity)
+	_, ok = world.Lookup("player")
+	fmt.Println(ok) // prints false
+}
+
+func TestEntitiesDefaults(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.RegisterDefault(&world, Position{X: 100, Y: 100})
+	headID := ecs.RegisterConstructor(&world, func() Heading { return Heading{Angle: 90} })
+
+	entity := world.NewEntity(posID, headID)
+	world.Batch().New(100, posID, headID)
+
+	pos := (*Position)(world.Get(entity, posID))
+	fmt.Println(pos.X, pos.Y) // prints 100 100
+}
+
+func TestEntitiesReserve(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	world.NewEntity(posID)
+
+	spawned := []ecs.Entity{}
+	query := world.Query(ecs.All(posID))
+	for query.Next() {
+		// Can't create entities here, as the world is locked.
+		spawned = append(spawned, world.Reserve())
+	}
+
+	for _, entity := range spawned {
+		world.Materialize(entity, posID)
+	}
+	fmt.Println(world.Alive(spawned[0])) // prints true
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/mlange-42/arche/ecs"
@@ -72,3 +73,16 @@ func TestListeners(t *testing.T) {
 	// Set it as the world's listener.
 	world.SetListener(&dispatch)
 }
+
+func TestHooks(t *testing.T) {
+	world := ecs.NewWorld()
+
+	posID := ecs.RegisterHooks[Position](&world,
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { fmt.Println("Added Position to", entity) },
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { fmt.Println("Removing Position from", entity) },
+		nil,
+	)
+
+	entity := world.NewEntity(posID)
+	world.RemoveEntity(entity)
+}
@@ -3,6 +3,7 @@ package userguide
 import (
 	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/mlange-42/arche/ecs"
@@ -54,6 +55,27 @@ func TestQueryIterateGeneric(t *testing.T) {
 	}
 }
 
+func TestQueryRead(t *testing.T) {
+	world := ecs.NewWorld()
+
+	filter := generic.NewFilter2[Position, Velocity]()
+	filter.Register(&world)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			query := filter.QueryRead(&world)
+			for query.Next() {
+				pos, vel := query.Get()
+				_, _ = pos, vel
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestQueryRemoveEntities(t *testing.T) {
 	world := ecs.NewWorld()
 
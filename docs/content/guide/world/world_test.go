@@ -28,3 +28,26 @@ func TestWorldReset(t *testing.T) {
 	world.Reset()
 	// ... start over again
 }
+
+func TestWorldTransaction(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	entity := world.NewEntity(posID)
+
+	tx := world.Begin()
+	// ... try something speculatively
+	world.RemoveEntity(entity)
+
+	// Revert all changes since Begin, or keep them with tx.Commit().
+	tx.Rollback()
+
+	if !world.Alive(entity) {
+		t.Fatal("entity should be alive after rollback")
+	}
+}
+
+type Position struct {
+	X float64
+	Y float64
+}
@@ -77,6 +77,7 @@ type archetypeData struct {
 	buffers      []reflect.Value // Reflection arrays containing component data.
 	indices      idMap[uint32]   // Mapping from IDs to buffer indices.
 	index        int32           // Index of the archetype in the world.
+	stamps       archetypeStamps // Stamps of handed-out component pointers. Only used with build tag `debug`.
 }
 
 // Init initializes an archetype
@@ -170,6 +171,27 @@ func (a *archetype) Remove(index uint32) bool {
 	return swapped
 }
 
+// RemoveOrdered removes an entity and its components from the archetype,
+// and shifts all subsequent entities to preserve their order.
+func (a *archetype) RemoveOrdered(index uint32) {
+	old := a.len - 1
+
+	if index != old {
+		count := old - index
+		a.shift(a.entityPointer, entitySize, index, count)
+		for _, id := range a.node.Ids {
+			lay := a.getLayout(id)
+			if lay.itemSize == 0 {
+				continue
+			}
+			a.shift(lay.pointer, lay.itemSize, index, count)
+		}
+	}
+
+	a.ZeroAll(old)
+	a.len--
+}
+
 // ZeroAll resets a block of storage in all buffers.
 func (a *archetype) ZeroAll(index uint32) {
 	for _, id := range a.node.Ids {
@@ -272,6 +294,7 @@ func (a *archetype) CopyEntitiesFrom(other *archetype, startIndex uint32) {
 //
 // Does NOT free the reserved memory.
 func (a *archetype) Reset() {
+	a.resetStamps()
 	if a.len == 0 {
 		return
 	}
@@ -373,12 +396,20 @@ func (a *archetype) copy(src, dst unsafe.Pointer, itemSize uint32) {
 	copy(dstSlice, srcSlice)
 }
 
+// shift moves count items of the given size, starting after index, one position towards the front.
+func (a *archetype) shift(base unsafe.Pointer, itemSize uint32, index uint32, count uint32) {
+	dst := unsafe.Slice((*byte)(unsafe.Add(base, index*itemSize)), count*itemSize)
+	src := unsafe.Slice((*byte)(unsafe.Add(base, (index+1)*itemSize)), count*itemSize)
+	copy(dst, src)
+}
+
 // extend the memory buffers if necessary for adding an entity.
 func (a *archetype) extend(by uint32) {
 	required := a.len + by
 	if a.cap >= required {
 		return
 	}
+	a.retireStamps()
 	for a.cap < required {
 		a.cap *= 2
 	}
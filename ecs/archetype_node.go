@@ -26,8 +26,10 @@ type nodeData struct {
 	zeroValue       []byte                // Used as source for setting storage to zero
 	archetypes      pagedSlice[archetype] // Storage for archetypes in nodes with entity relation
 	archetypeData   pagedSlice[archetypeData]
-	neighbors       idMap[*archNode] // Mapping from component ID to add/remove, to the resulting archetype
-	initialCapacity uint32           // Initial capacity of component columns
+	neighbors       idMap[*archNode]    // Mapping from component ID to add/remove, to the resulting archetype
+	ordered         pointers[archetype] // Active archetypes in nodes with entity relation, sorted by target. Only used in deterministic mode.
+	initialCapacity uint32              // Initial capacity of component columns
+	deterministic   bool                // Whether archetypes are iterated in order of their targets.
 }
 
 // Creates a new archNode
@@ -90,6 +92,9 @@ func (a *archNode) Matches(f Filter) bool {
 // Returns nil if the node has no archetype(s).
 func (a *archNode) Archetypes() archetypes {
 	if a.archetype == nil {
+		if a.deterministic {
+			return &a.ordered
+		}
 		return &a.archetypes
 	}
 	return singleArchetype{Archetype: a.archetype}
@@ -131,6 +136,9 @@ func (a *archNode) CreateArchetype(layouts uint8, target Entity) *archetype {
 		arch.Init(a, a.archetypeData.Get(archIndex), archIndex, true, layouts, target)
 	}
 	a.archetypeMap[target] = arch
+	if a.deterministic {
+		a.ordered.pointers = sortedInsert(a.ordered.pointers, arch)
+	}
 	return arch
 }
 
@@ -152,10 +160,30 @@ func (a *archNode) ExtendArchetypeLayouts(count uint8) {
 	}
 }
 
+// SetDeterministic sets whether archetypes are iterated in order of their relation targets.
+func (a *archNode) SetDeterministic(deterministic bool) {
+	a.deterministic = deterministic
+	a.ordered.pointers = a.ordered.pointers[:0]
+	if !deterministic || !a.HasRelation {
+		return
+	}
+	lenArches := a.archetypes.Len()
+	var j int32
+	for j = 0; j < lenArches; j++ {
+		arch := a.archetypes.Get(j)
+		if arch.IsActive() {
+			a.ordered.pointers = sortedInsert(a.ordered.pointers, arch)
+		}
+	}
+}
+
 // RemoveArchetype de-activates an archetype.
 // The archetype will be re-used by CreateArchetype.
 func (a *archNode) RemoveArchetype(arch *archetype) {
 	delete(a.archetypeMap, arch.RelationTarget)
+	if a.deterministic {
+		a.ordered.pointers = orderedRemove(a.ordered.pointers, arch)
+	}
 	idx := arch.index
 	a.freeIndices = append(a.freeIndices, idx)
 	a.archetypes.Get(idx).Deactivate()
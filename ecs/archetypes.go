@@ -33,6 +33,7 @@ type batchArchetypes struct {
 	OldArchetype []*archetype
 	Added        []ID
 	Removed      []ID
+	silent       bool // Whether to skip notification when a query over the batch is closed.
 }
 
 // Get returns the value at the given index.
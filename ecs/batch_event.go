@@ -0,0 +1,79 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/event"
+
+// BatchEvent contains information about a batch operation on a contiguous range of entities in one archetype.
+//
+// Batch events are emitted instead of per-entity [EntityEvent]s to listeners implementing [BatchListener].
+// All entities covered by an event share the same component composition and relation target,
+// and hence all fields except the entity.
+//
+// The covered entities can be iterated with [BatchEvent.Query], or accessed with [BatchEvent.EntityAt].
+// Per-entity events can be obtained with [BatchEvent.Event].
+type BatchEvent struct {
+	OldRelation, NewRelation *ID                // Old and new relation component ID. No relation is indicated by nil.
+	AddedIDs, RemovedIDs     []ID               // Components added and removed. DO NOT MODIFY!
+	Added, Removed           Mask               // Masks indicating changed components (additions and removals).
+	Mask                     Mask               // Archetype mask of the entities after the operation.
+	OldTarget                Entity             // Old relation target entity.
+	NewTarget                Entity             // New relation target entity.
+	EventTypes               event.Subscription // Bit mask of event types. See [event.Subscription].
+	world                    *World             // The world of the event.
+	archetype                *archetype         // The archetype of the entities.
+	start, end               uint32             // Range of entities in the archetype.
+}
+
+// Contains returns whether the event's types contain the given type/subscription bit.
+func (e *BatchEvent) Contains(bit event.Subscription) bool {
+	return e.EventTypes.Contains(bit)
+}
+
+// Len returns the number of entities covered by the event.
+func (e *BatchEvent) Len() int {
+	return int(e.end - e.start)
+}
+
+// EntityAt returns the entity at the given index, in the range [0, [BatchEvent.Len]).
+// Panics if the index is out of range.
+func (e *BatchEvent) EntityAt(index int) Entity {
+	if index < 0 || index >= e.Len() {
+		panic("batch event index out of range")
+	}
+	return e.archetype.GetEntity(e.start + uint32(index))
+}
+
+// Event returns the per-entity [EntityEvent] for the entity at the given index.
+// Panics if the index is out of range.
+func (e *BatchEvent) Event(index int) EntityEvent {
+	return EntityEvent{
+		Entity: e.EntityAt(index), Added: e.Added, Removed: e.Removed,
+		AddedIDs: e.AddedIDs, RemovedIDs: e.RemovedIDs,
+		OldRelation: e.OldRelation, NewRelation: e.NewRelation,
+		OldTarget: e.OldTarget, NewTarget: e.NewTarget, EventTypes: e.EventTypes,
+	}
+}
+
+// Query returns a [Query] over the entities covered by the event.
+//
+// Locks the world. The lock is released when the query finishes iteration, or when [Query.Close] is called.
+// Closing the query does not emit any further events.
+func (e *BatchEvent) Query() Query {
+	batch := batchArchetypes{silent: true}
+	batch.Add(e.archetype, nil, e.start, e.end)
+	return newBatchQuery(e.world, e.world.lock(), &batch)
+}
+
+// BatchListener is an optional extension of [Listener] for aggregated events of batch operations.
+//
+// For batch operations that create entities or change their components or relation targets
+// (see [Batch], [Builder.NewBatch] and [Relations.SetBatch]), listeners implementing BatchListener
+// receive one [BatchEvent] per affected archetype range, instead of one [EntityEvent] per entity.
+// Batch entity removal still emits per-entity events.
+//
+// Subscriptions of batch events are determined by [Listener].Subscriptions and [Listener].Components,
+// like for per-entity events.
+type BatchListener interface {
+	Listener
+	// NotifyBatch notifies the listener about a subscribed batch event.
+	NotifyBatch(world *World, evt BatchEvent)
+}
@@ -0,0 +1,108 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBatchListener struct {
+	testListener
+	batches []BatchEvent
+}
+
+func (l *testBatchListener) NotifyBatch(world *World, evt BatchEvent) {
+	l.batches = append(l.batches, evt)
+}
+
+func TestBatchEvent(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[ChildOf](&w)
+
+	entities := 0
+	ls := testBatchListener{
+		testListener: newTestListener(func(world *World, e EntityEvent) { entities++ }),
+	}
+	w.SetListener(&ls)
+
+	w.Batch().New(10, posID)
+	assert.Equal(t, 0, entities)
+	assert.Equal(t, 1, len(ls.batches))
+
+	evt := ls.batches[0]
+	assert.Equal(t, 10, evt.Len())
+	assert.True(t, evt.Contains(event.EntityCreated))
+	assert.True(t, evt.Contains(event.ComponentAdded))
+	assert.Equal(t, All(posID), evt.Mask)
+	assert.Equal(t, []ID{posID}, evt.AddedIDs)
+
+	query := evt.Query()
+	assert.Equal(t, 10, query.Count())
+	cnt := 0
+	for query.Next() {
+		assert.Equal(t, evt.EntityAt(cnt), query.Entity())
+		cnt++
+	}
+	assert.Equal(t, 10, cnt)
+	assert.False(t, w.IsLocked())
+	assert.Equal(t, 1, len(ls.batches))
+
+	e := evt.Event(3)
+	assert.Equal(t, evt.EntityAt(3), e.Entity)
+	assert.Equal(t, evt.EventTypes, e.EventTypes)
+	assert.Panics(t, func() { evt.EntityAt(10) })
+	assert.Panics(t, func() { evt.EntityAt(-1) })
+
+	w.Batch().New(5, velID)
+	w.Batch().Add(All(), relID)
+	assert.Equal(t, 0, entities)
+	assert.Equal(t, 4, len(ls.batches))
+	assert.Equal(t, 10, ls.batches[2].Len())
+	assert.Equal(t, All(relID), ls.batches[2].Added)
+	assert.Equal(t, All(posID, relID), ls.batches[2].Mask)
+
+	parent := w.NewEntity()
+	assert.Equal(t, 1, entities)
+	w.Relations().SetBatch(All(relID), relID, parent)
+	assert.Equal(t, 6, len(ls.batches))
+	assert.Equal(t, parent, ls.batches[5].NewTarget)
+	assert.True(t, ls.batches[5].Contains(event.TargetChanged))
+
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(20, parent)
+	assert.Equal(t, 7, len(ls.batches))
+	assert.Equal(t, 20, ls.batches[6].Len())
+	assert.Equal(t, parent, ls.batches[6].NewTarget)
+
+	w.Batch().RemoveEntities(All())
+	assert.Equal(t, 1+1+10+5+20, entities)
+}
+
+func TestBatchEventFallback(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	entities := 0
+	ls := newTestListener(func(world *World, e EntityEvent) { entities++ })
+	batch := testBatchListener{
+		testListener: newTestListener(func(world *World, e EntityEvent) {}),
+	}
+	w.AddListener(&ls)
+	w.AddListener(&batch)
+
+	e := w.NewEntity()
+	watched := 0
+	w.Watch(e, event.All, func(world *World, evt EntityEvent) { watched++ })
+
+	w.Batch().New(10, posID)
+	assert.Equal(t, 11, entities)
+	assert.Equal(t, 1, len(batch.batches))
+
+	filter := All().Exclusive()
+	w.Batch().Add(&filter, posID)
+	assert.Equal(t, 12, entities)
+	assert.Equal(t, 2, len(batch.batches))
+	assert.Equal(t, 1, watched)
+}
@@ -24,7 +24,9 @@ type Cache struct {
 	indices       map[uint32]int              // Mapping from filter IDs to indices in filters
 	filters       []cacheEntry                // The cached filters, indexed by indices
 	getArchetypes func(f Filter) []*archetype // Callback for getting archetypes for a new filter from the world
+	unregistered  func(id uint32)             // Callback for notifying the world about an unregistered filter
 	intPool       intPool[uint32]             // Pool for filter IDs
+	deterministic bool                        // Whether archetypes are kept in deterministic order
 }
 
 // newCache creates a new [Cache].
@@ -47,6 +49,9 @@ func (c *Cache) Register(f Filter) CachedFilter {
 	if _, ok := f.(*CachedFilter); ok {
 		panic("filter is already registered")
 	}
+	if _, ok := f.(*PredicateFilter); ok {
+		panic("can't register a predicate filter. Register the underlying filter instead")
+	}
 	id := c.intPool.Get()
 	c.filters = append(c.filters,
 		cacheEntry{
@@ -69,6 +74,9 @@ func (c *Cache) Unregister(f *CachedFilter) Filter {
 	}
 	filter := c.filters[idx].Filter
 	delete(c.indices, f.id)
+	if c.unregistered != nil {
+		c.unregistered(f.id)
+	}
 
 	last := len(c.filters) - 1
 	if idx != last {
@@ -95,6 +103,10 @@ func (c *Cache) get(f *CachedFilter) *cacheEntry {
 //
 // Iterates over all filters and adds the node to the resp. entry where the filter matches.
 func (c *Cache) addArchetype(arch *archetype) {
+	if c.deterministic {
+		c.addArchetypeOrdered(arch)
+		return
+	}
 	if !arch.HasRelation() {
 		for i := range c.filters {
 			e := &c.filters[i]
@@ -132,6 +144,10 @@ func (c *Cache) addArchetype(arch *archetype) {
 // Can only be used for archetypes that have a relation target.
 // Archetypes without a relation are never removed.
 func (c *Cache) removeArchetype(arch *archetype) {
+	if c.deterministic {
+		c.removeArchetypeOrdered(arch)
+		return
+	}
 	for i := range c.filters {
 		e := &c.filters[i]
 
@@ -157,3 +173,45 @@ func (c *Cache) mapArchetypes(e *cacheEntry) {
 		}
 	}
 }
+
+// Adds an archetype in deterministic mode, keeping the archetypes of each entry sorted.
+// Invalidates the archetype indices of affected entries.
+func (c *Cache) addArchetypeOrdered(arch *archetype) {
+	for i := range c.filters {
+		e := &c.filters[i]
+		if !e.Filter.Matches(&arch.Mask) {
+			continue
+		}
+		if rf, ok := e.Filter.(*RelationFilter); ok && rf.Target != arch.RelationTarget {
+			continue
+		}
+		e.Archetypes.pointers = sortedInsert(e.Archetypes.pointers, arch)
+		e.Indices = nil
+	}
+}
+
+// Removes an archetype in deterministic mode, preserving the order of the remaining archetypes.
+// Invalidates the archetype indices of affected entries.
+func (c *Cache) removeArchetypeOrdered(arch *archetype) {
+	for i := range c.filters {
+		e := &c.filters[i]
+		if !e.Filter.Matches(&arch.Mask) {
+			continue
+		}
+		e.Archetypes.pointers = orderedRemove(e.Archetypes.pointers, arch)
+		e.Indices = nil
+	}
+}
+
+// Sets whether archetypes are kept in deterministic order, and sorts all entries if so.
+func (c *Cache) setDeterministic(deterministic bool) {
+	c.deterministic = deterministic
+	if !deterministic {
+		return
+	}
+	for i := range c.filters {
+		e := &c.filters[i]
+		sortArchetypes(e.Archetypes.pointers)
+		e.Indices = nil
+	}
+}
@@ -8,6 +8,8 @@ type config struct {
 	// Initial capacity for archetypes with a relation component.
 	// The default value is initialCapacity.
 	initialCapacityRelations int
+	// Whether the iteration order is deterministic. See [World.SetDeterministic].
+	deterministic bool
 }
 
 // newConfig creates a new default [World] configuration.
@@ -0,0 +1,115 @@
+package ecs
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// componentDefaults holds default values and constructors of component types.
+// See [RegisterDefault] and [RegisterConstructor].
+type componentDefaults struct {
+	values       []unsafe.Pointer            // Default values by component ID. Nil if no defaults are registered.
+	constructors []func(comp unsafe.Pointer) // Constructors by component ID. Nil if no defaults are registered.
+	mask         Mask                        // Components that have a default value or a constructor.
+}
+
+// RegisterDefault registers a default value for a component type, and returns the type's [ID].
+// Registers the type if it is not already registered.
+//
+// The default value is assigned to the component whenever it is added to an entity,
+// instead of the zero value. This includes entity creation, [World.Add], [World.Exchange],
+// [Builder], [Batch] operations and the generic API, like [github.com/mlange-42/arche/generic.Map1.New].
+// For batch operations, component columns are initialized in bulk.
+//
+// Values given on creation or addition, like via [World.Assign] or [github.com/mlange-42/arche/generic.Map1.NewWith],
+// overwrite the default value. Hooks registered via [RegisterHooks] see the assigned values.
+//
+// The value is copied shallowly. Thus, pointers, slices and maps in the default value are shared
+// between all entities. For such types, use [RegisterConstructor] instead.
+//
+// Panics if a default value or constructor is already registered for the component type.
+func RegisterDefault[T any](w *World, value T) ID {
+	id := ComponentID[T](w)
+	w.defaults.checkRegistered(id, &w.registry)
+	ptr := new(T)
+	*ptr = value
+	w.defaults.register(id, unsafe.Pointer(ptr), nil)
+	return id
+}
+
+// RegisterConstructor registers a constructor for a component type, and returns the type's [ID].
+// Registers the type if it is not already registered.
+//
+// The constructor is called for each entity the component is added to, and its result is assigned to the component,
+// instead of the zero value. It is applied in the same situations as default values registered with [RegisterDefault].
+// In contrast to default values, constructors can create a separate map, slice or buffer for each entity.
+// However, they are slower for batch operations, as they are called for each entity individually.
+//
+// Constructors are called while the entity is being created or modified.
+// Thus, they must not access the world.
+//
+// Panics if a default value or constructor is already registered for the component type.
+func RegisterConstructor[T any](w *World, fn func() T) ID {
+	id := ComponentID[T](w)
+	w.defaults.checkRegistered(id, &w.registry)
+	w.defaults.register(id, nil, func(comp unsafe.Pointer) { *(*T)(comp) = fn() })
+	return id
+}
+
+// checkRegistered panics if a default is already registered for a component.
+func (d *componentDefaults) checkRegistered(id ID, reg *componentRegistry) {
+	if d.mask.Get(id) {
+		panic(fmt.Sprintf("default for component type %v is already registered", reg.Types[id.id]))
+	}
+}
+
+// register sets the default value or the constructor of a component.
+func (d *componentDefaults) register(id ID, value unsafe.Pointer, constructor func(comp unsafe.Pointer)) {
+	if d.values == nil {
+		d.values = make([]unsafe.Pointer, MaskTotalBits)
+		d.constructors = make([]func(comp unsafe.Pointer), MaskTotalBits)
+	}
+	d.values[id.id] = value
+	d.constructors[id.id] = constructor
+	d.mask.Set(id, true)
+}
+
+// apply initializes a range of rows of a component column in an archetype.
+//
+// Default values are copied into the first row, and then the initialized block is doubled until the range is filled.
+func (d *componentDefaults) apply(arch *archetype, id ID, start, count uint32) {
+	lay := arch.getLayout(id)
+	size := lay.itemSize
+	if size == 0 || count == 0 {
+		return
+	}
+	if fn := d.constructors[id.id]; fn != nil {
+		var i uint32
+		for i = 0; i < count; i++ {
+			fn(lay.Get(start + i))
+		}
+		return
+	}
+	dst := unsafe.Slice((*byte)(lay.Get(start)), size*count)
+	copy(dst[:size], unsafe.Slice((*byte)(d.values[id.id]), size))
+	for done := size; done < uint32(len(dst)); done *= 2 {
+		copy(dst[done:], dst[:done])
+	}
+}
+
+// applyDefaults initializes the given components of the entity at an archetype row with their defaults.
+func (w *World) applyDefaults(arch *archetype, index uint32, comps []ID) {
+	w.applyDefaultsRange(arch, index, 1, comps)
+}
+
+// applyDefaultsRange initializes the given components of a range of archetype rows with their defaults.
+func (w *World) applyDefaultsRange(arch *archetype, start, count uint32, comps []ID) {
+	if !arch.Mask.ContainsAny(&w.defaults.mask) {
+		return
+	}
+	for _, id := range comps {
+		if w.defaults.mask.Get(id) {
+			w.defaults.apply(arch, id, start, count)
+		}
+	}
+}
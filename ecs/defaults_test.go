@@ -0,0 +1,149 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDefault(t *testing.T) {
+	w := NewWorld()
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	posID := RegisterDefault(&w, Position{X: 1, Y: 2})
+	assert.Equal(t, posID, ComponentID[Position](&w))
+
+	assert.PanicsWithValue(t, "default for component type ecs.Position is already registered",
+		func() { RegisterDefault(&w, Position{}) })
+	assert.PanicsWithValue(t, "default for component type ecs.Position is already registered",
+		func() { RegisterConstructor(&w, func() Position { return Position{} }) })
+
+	e1 := w.NewEntity(posID, velID)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e1, posID)))
+	assert.Equal(t, Velocity{}, *(*Velocity)(w.Get(e1, velID)))
+
+	e2 := w.NewEntityWith(Component{ID: posID, Comp: &Position{X: 5}})
+	assert.Equal(t, Position{X: 5}, *(*Position)(w.Get(e2, posID)))
+
+	e3 := w.NewEntity(velID)
+	(*Velocity)(w.Get(e3, velID)).X = 3
+	w.Add(e3, posID)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e3, posID)))
+	assert.Equal(t, Velocity{X: 3}, *(*Velocity)(w.Get(e3, velID)))
+
+	(*Position)(w.Get(e3, posID)).X = 10
+	w.Exchange(e3, []ID{relID}, []ID{velID})
+	assert.Equal(t, Position{X: 10, Y: 2}, *(*Position)(w.Get(e3, posID)))
+
+	w.Exchange(e3, []ID{velID}, []ID{posID})
+	w.Exchange(e3, []ID{posID}, nil)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e3, posID)))
+
+	w.Remove(e3, posID)
+	w.Assign(e3, Component{ID: posID, Comp: &Position{X: 7}})
+	assert.Equal(t, Position{X: 7}, *(*Position)(w.Get(e3, posID)))
+
+	e4 := NewBuilder(&w, posID, relID).WithRelation(relID).New(e1)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e4, posID)))
+
+	r := w.Reserve()
+	w.Materialize(r, posID)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(r, posID)))
+
+	w.RemoveEntity(e1)
+	e5 := w.NewEntity(posID)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e5, posID)))
+
+	assert.NoError(t, w.Validate())
+}
+
+func TestRegisterDefaultBatch(t *testing.T) {
+	w := NewWorld()
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	posID := RegisterDefault(&w, Position{X: 1, Y: 2})
+
+	checkAll := func(filter Filter, expected int) {
+		query := w.Query(filter)
+		assert.Equal(t, expected, query.Count())
+		for query.Next() {
+			assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(query.Get(posID)))
+		}
+	}
+
+	w.Batch().New(7, posID)
+	checkAll(All(posID), 7)
+
+	query := w.Batch().NewQ(5, posID, velID)
+	for query.Next() {
+		assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(query.Get(posID)))
+	}
+
+	w.Batch().New(3, velID)
+	filter := All(velID).Without(posID)
+	w.Batch().Add(&filter, posID)
+	checkAll(All(posID, velID), 8)
+
+	w.Batch().Exchange(All(velID), []ID{relID}, []ID{posID})
+	w.Batch().Exchange(All(velID), []ID{posID}, []ID{relID})
+	checkAll(All(posID, velID), 8)
+
+	parent := w.NewEntity()
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(9, parent)
+	checkAll(All(posID, relID), 9)
+
+	builder := NewBuilderWith(&w, Component{ID: posID, Comp: &Position{X: 3}})
+	query = builder.NewBatchQ(4)
+	assert.Equal(t, 4, query.Count())
+	for query.Next() {
+		assert.Equal(t, Position{X: 3}, *(*Position)(query.Get(posID)))
+	}
+
+	assert.NoError(t, w.Validate())
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	w := NewWorld()
+	calls := 0
+	sliceID := RegisterConstructor(&w, func() SliceType {
+		calls++
+		return SliceType{Slice: make([]int, 1, 4)}
+	})
+
+	e1 := w.NewEntity(sliceID)
+	e2 := w.NewEntity(sliceID)
+	assert.Equal(t, 2, calls)
+
+	s1 := (*SliceType)(w.Get(e1, sliceID))
+	s2 := (*SliceType)(w.Get(e2, sliceID))
+	s1.Slice[0] = 1
+	assert.Equal(t, []int{1}, s1.Slice)
+	assert.Equal(t, []int{0}, s2.Slice)
+
+	query := w.Batch().NewQ(5, sliceID)
+	assert.Equal(t, 7, calls)
+	for query.Next() {
+		s := (*SliceType)(query.Get(sliceID))
+		assert.Equal(t, []int{0}, s.Slice)
+		s.Slice[0] = 2
+	}
+	assert.Equal(t, []int{0}, s2.Slice)
+
+	assert.NoError(t, w.Validate())
+}
+
+func TestRegisterDefaultHooks(t *testing.T) {
+	w := NewWorld()
+	RegisterDefault(&w, Position{X: 1, Y: 2})
+
+	added := []Position{}
+	posID := RegisterHooks[Position](&w,
+		func(w *World, entity Entity, pos *Position) { added = append(added, *pos) },
+		nil, nil,
+	)
+
+	w.NewEntity(posID)
+	w.NewEntityWith(Component{ID: posID, Comp: &Position{X: 5}})
+	w.Batch().New(2, posID)
+	assert.Equal(t, []Position{{X: 1, Y: 2}, {X: 5}, {X: 1, Y: 2}, {X: 1, Y: 2}}, added)
+}
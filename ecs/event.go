@@ -25,16 +25,19 @@ import "github.com/mlange-42/arche/ecs/event"
 // Events for removed entities are fired right before removal of the entity,
 // to allow for inspection of its components.
 // Therefore, the [World] is in a locked state during entity removal events.
+// For deferred delivery in an unlocked state, see [github.com/mlange-42/arche/listener.Buffered].
 //
 // Events for batch-creation of entities using a [Builder] are fired after all entities are created.
 // For batch methods that return a [Query], events are fired after the [Query] is closed (or fully iterated).
 // This allows the [World] to be in an unlocked state, and notifies after potential entity initialization.
+// Listeners that implement [BatchListener] receive one aggregated [BatchEvent] per archetype for these operations.
 type EntityEvent struct {
 	OldRelation, NewRelation *ID                // Old and new relation component ID. No relation is indicated by nil.
 	AddedIDs, RemovedIDs     []ID               // Components added and removed. DO NOT MODIFY! Get the current components with [World.Ids].
 	Added, Removed           Mask               // Masks indicating changed components (additions and removals).
 	Entity                   Entity             // The entity that was changed.
-	OldTarget                Entity             // Old relation target entity. Get the new target with [World.Relations] and [Relations.Get].
+	OldTarget                Entity             // Old relation target entity.
+	NewTarget                Entity             // New relation target entity.
 	EventTypes               event.Subscription // Bit mask of event types. See [event.Subscription].
 }
 
@@ -46,7 +49,9 @@ func (e *EntityEvent) Contains(bit event.Subscription) bool {
 // Listener interface for listening to [EntityEvent] notifications
 // on ECS operations like entity creation and removal, component addition and removal, and relation changes.
 //
-// A listener can be added to a [World] with [World.SetListener].
+// A listener can be set for a [World] with [World.SetListener].
+// Further listeners can be added and removed with [World.AddListener] and [World.RemoveListener].
+// To observe single entities or relation targets, see [World.Watch] and [World.WatchTarget].
 //
 // # Subscriptions
 //
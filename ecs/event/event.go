@@ -1,6 +1,7 @@
-// Package event contains a mask type and bit switches for listener subscriptions.
+// Package event contains mask types and bit switches for listener subscriptions.
 //
-// See also [github.com/mlange-42/arche/ecs.Listener] and [github.com/mlange-42/arche/ecs.EntityEvent].
+// See also [github.com/mlange-42/arche/ecs.Listener], [github.com/mlange-42/arche/ecs.EntityEvent]
+// and [github.com/mlange-42/arche/ecs.ResourceListener].
 package event
 
 // Subscription bits for an [github.com/mlange-42/arche/ecs.Listener]
@@ -88,3 +89,37 @@ const (
 	// All subscriptions
 	All Subscription = Entities | Components | Relations
 )
+
+// ResourceSubscription bits for an [github.com/mlange-42/arche/ecs.ResourceListener]
+type ResourceSubscription uint8
+
+// Contains checks whether all the argument's bits are contained in this ResourceSubscription.
+func (s ResourceSubscription) Contains(bits ResourceSubscription) bool {
+	return (bits & s) == bits
+}
+
+// ContainsAny checks whether any of the argument's bits are contained in this ResourceSubscription.
+func (s ResourceSubscription) ContainsAny(bits ResourceSubscription) bool {
+	return (bits & s) != 0
+}
+
+// ResourceSubscription bits for individual resource events.
+const (
+	// ResourceAdded subscription bit.
+	//   - Addition of a resource to the world
+	ResourceAdded ResourceSubscription = 1
+
+	// ResourceRemoved subscription bit.
+	//   - Removal of a resource from the world
+	ResourceRemoved ResourceSubscription = 1 << 1
+
+	// ResourceChanged subscription bit.
+	//   - Explicit replacement or change notification of a resource
+	ResourceChanged ResourceSubscription = 1 << 2
+)
+
+// ResourceSubscription bits for groups of resource events
+const (
+	// Resources subscription for all resource events
+	Resources ResourceSubscription = ResourceAdded | ResourceRemoved | ResourceChanged
+)
@@ -18,6 +18,17 @@ func TestSubscriptions(t *testing.T) {
 	assert.False(t, m1.Contains(event.ComponentAdded|event.RelationChanged))
 }
 
+func TestResourceSubscriptions(t *testing.T) {
+	m1 := event.ResourceAdded | event.ResourceChanged
+
+	assert.True(t, m1.Contains(event.ResourceAdded))
+	assert.False(t, m1.Contains(event.ResourceRemoved))
+
+	assert.True(t, m1.ContainsAny(event.ResourceRemoved|event.ResourceChanged))
+	assert.False(t, m1.Contains(event.Resources))
+	assert.True(t, event.Resources.Contains(m1))
+}
+
 func ExampleSubscription() {
 	mask := event.EntityCreated | event.EntityRemoved
 
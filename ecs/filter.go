@@ -1,9 +1,12 @@
 package ecs
 
+import "unsafe"
+
 // Filter is the interface for logic filters.
 // Filters are required to query entities using [World.Query].
 //
-// See [Mask], [MaskFilter] anf [RelationFilter] for basic filters.
+// See [Mask], [MaskFilter] anf [RelationFilter] for basic filters,
+// and [PredicateFilter] for filtering by component values.
 // For type-safe generics queries, see package [github.com/mlange-42/arche/generic].
 // For advanced filtering, see package [github.com/mlange-42/arche/filter].
 type Filter interface {
@@ -59,3 +62,40 @@ type CachedFilter struct {
 func (f *CachedFilter) Matches(bits *Mask) bool {
 	return f.filter.Matches(bits)
 }
+
+// PredicateFilter is a [Filter] that additionally checks a predicate on component values.
+//
+// The predicate is evaluated for each entity during [Query] iteration,
+// and entities for which it returns false are skipped.
+// It receives pointers to the given components of the current entity, in the given order.
+// Pointers are nil for components the entity does not have.
+// The slice of pointers is re-used between calls and must not be stored.
+//
+// A PredicateFilter must be the outermost filter passed to [World.Query].
+// It can wrap any other filter, including a [RelationFilter] or a [CachedFilter].
+// However, it can't be registered for caching itself, and it can't be used in batch operations.
+//
+// As predicates are evaluated per entity, [Query.Count], [Query.EntityAt] and [Query.Step]
+// iterate over the entities of all matching archetypes for queries with a PredicateFilter.
+type PredicateFilter struct {
+	Filter     Filter                            // Components filter.
+	Components []ID                              // Components passed to the predicate.
+	Predicate  func(comps []unsafe.Pointer) bool // Predicate on the components of an entity.
+}
+
+// NewPredicateFilter creates a new [PredicateFilter].
+// It is a [Filter] that additionally checks a predicate on the values of the given components.
+func NewPredicateFilter(filter Filter, predicate func(comps []unsafe.Pointer) bool, comps ...ID) PredicateFilter {
+	return PredicateFilter{
+		Filter:     filter,
+		Components: comps,
+		Predicate:  predicate,
+	}
+}
+
+// Matches the filter against a mask.
+//
+// Only checks the underlying filter. The predicate is evaluated during query iteration.
+func (f *PredicateFilter) Matches(bits *Mask) bool {
+	return f.Filter.Matches(bits)
+}
@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"testing"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -74,3 +75,117 @@ func ExampleRelationFilter() {
 	}
 	// Output:
 }
+
+func TestPredicateFilter(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	for i := 0; i < 10; i++ {
+		e := w.NewEntity(posID)
+		(*Position)(w.Get(e, posID)).X = i
+	}
+	for i := 0; i < 10; i++ {
+		e := w.NewEntity(posID, velID)
+		(*Position)(w.Get(e, posID)).X = i + 10
+	}
+
+	filter := NewPredicateFilter(All(posID), func(comps []unsafe.Pointer) bool {
+		return (*Position)(comps[0]).X%2 == 0
+	}, posID)
+
+	query := w.Query(&filter)
+	assert.Equal(t, 10, query.Count())
+	assert.Equal(t, 2, (*Position)(w.Get(query.EntityAt(1), posID)).X)
+	assert.Equal(t, 12, (*Position)(w.Get(query.EntityAt(6), posID)).X)
+	assert.Panics(t, func() { query.EntityAt(10) })
+
+	values := []int{}
+	for query.Next() {
+		values = append(values, (*Position)(query.Get(posID)).X)
+	}
+	assert.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, values)
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(&filter)
+	assert.True(t, query.Step(3))
+	assert.Equal(t, 4, (*Position)(query.Get(posID)).X)
+	assert.True(t, query.Step(4))
+	assert.Equal(t, 12, (*Position)(query.Get(posID)).X)
+	assert.False(t, query.Step(10))
+	assert.False(t, w.IsLocked())
+
+	cached := w.Cache().Register(All(posID, velID))
+	filter = NewPredicateFilter(&cached, func(comps []unsafe.Pointer) bool {
+		return (*Position)(comps[0]).X < 15 && comps[1] != nil
+	}, posID, velID)
+	query = w.Query(&filter)
+	assert.Equal(t, 5, query.Count())
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	assert.Equal(t, 5, cnt)
+
+	assert.Panics(t, func() { w.Cache().Register(&filter) })
+	assert.Panics(t, func() { w.Batch().RemoveEntities(&filter) })
+
+	nested := NewPredicateFilter(&filter, func(comps []unsafe.Pointer) bool { return true })
+	assert.Panics(t, func() { w.Query(&nested) })
+	assert.False(t, w.IsLocked())
+}
+
+func TestPredicateFilterRelation(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[ChildOf](&w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	builder := NewBuilder(&w, posID, relID).WithRelation(relID)
+	for i := 0; i < 10; i++ {
+		e := builder.New(parent1)
+		(*Position)(w.Get(e, posID)).X = i
+		e = builder.New(parent2)
+		(*Position)(w.Get(e, posID)).X = i
+	}
+
+	relFilter := NewRelationFilter(All(posID, relID), parent2)
+	filter := NewPredicateFilter(&relFilter, func(comps []unsafe.Pointer) bool {
+		return (*Position)(comps[0]).X >= 5
+	}, posID)
+
+	query := w.Query(&filter)
+	assert.Equal(t, 5, query.Count())
+	assert.Equal(t, 7, (*Position)(w.Get(query.EntityAt(2), posID)).X)
+	assert.Equal(t, parent2, w.Relations().Get(query.EntityAt(4), relID))
+	assert.Panics(t, func() { query.EntityAt(5) })
+
+	allocs := testing.AllocsPerRun(10, func() {
+		query.Count()
+		query.EntityAt(3)
+	})
+	assert.Equal(t, 0.0, allocs)
+
+	for query.Next() {
+		assert.Equal(t, parent2, query.Relation(relID))
+		assert.GreaterOrEqual(t, (*Position)(query.Get(posID)).X, 5)
+	}
+}
+
+func ExamplePredicateFilter() {
+	world := NewWorld()
+	posID := ComponentID[Position](&world)
+
+	filter := NewPredicateFilter(All(posID), func(comps []unsafe.Pointer) bool {
+		pos := (*Position)(comps[0])
+		return pos.X > 10
+	}, posID)
+
+	query := world.Query(&filter)
+	for query.Next() {
+		// ...
+	}
+	// Output:
+}
@@ -0,0 +1,577 @@
+package ecs_test
+
+import (
+	"math/rand"
+	"testing"
+	"unsafe"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// FuzzWorld applies random operation sequences to an [ecs.World] and to the reference model [refWorld],
+// and compares entities, component values, relation targets, query results and emitted events.
+//
+// Run with:
+//
+//	go test -fuzz FuzzWorld ./ecs
+func FuzzWorld(f *testing.F) {
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 16; i++ {
+		data := make([]byte, 256+32*i)
+		rng.Read(data)
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h := newFuzzHarness(t, data)
+		for h.input.Len() > 0 {
+			h.Step()
+		}
+		h.Check()
+	})
+}
+
+// fuzzInput reads bytes from fuzzer-provided data. Returns zeros when exhausted.
+type fuzzInput struct {
+	data []byte
+}
+
+// Len returns the number of remaining bytes.
+func (in *fuzzInput) Len() int {
+	return len(in.data)
+}
+
+// Byte reads the next byte.
+func (in *fuzzInput) Byte() byte {
+	if len(in.data) == 0 {
+		return 0
+	}
+	b := in.data[0]
+	in.data = in.data[1:]
+	return b
+}
+
+// Int reads a non-negative integer below n.
+func (in *fuzzInput) Int(n int) int {
+	return int(in.Byte()) % n
+}
+
+// Bool reads a boolean.
+func (in *fuzzInput) Bool() bool {
+	return in.Byte()&1 == 1
+}
+
+// Mask reads a bit mask of component kinds.
+func (in *fuzzInput) Mask() uint8 {
+	return in.Byte() & refAll
+}
+
+// fuzzListener records normalized events of the world under test.
+type fuzzListener struct {
+	h      *fuzzHarness
+	events []refEvent
+}
+
+func (l *fuzzListener) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	if l.h.tx != nil || l.h.committing {
+		// Events of transactions are deferred, and reflect the state at their time.
+		return
+	}
+	e := refEvent{
+		Entity:    evt.Entity,
+		Created:   evt.Contains(event.EntityCreated),
+		Removed:   evt.Contains(event.EntityRemoved),
+		Added:     l.h.kinds(&evt.Added),
+		Dropped:   l.h.kinds(&evt.Removed),
+		OldTarget: evt.OldTarget,
+	}
+	if !e.Removed && world.Has(evt.Entity, l.h.ids[refR]) {
+		e.NewTarget = world.Relations().Get(evt.Entity, l.h.ids[refR])
+	}
+	l.events = append(l.events, e)
+}
+
+func (l *fuzzListener) Subscriptions() event.Subscription {
+	return event.All
+}
+
+func (l *fuzzListener) Components() *ecs.Mask {
+	return nil
+}
+
+// fuzzHarness applies operations to a world and the reference model, and compares them.
+type fuzzHarness struct {
+	t          *testing.T
+	input      fuzzInput
+	world      ecs.World
+	ref        *refWorld
+	listener   fuzzListener
+	ids        [refKinds]ecs.ID
+	step       int
+	tx         *ecs.Tx      // Innermost active transaction, if any.
+	committing bool         // Whether the outermost transaction is being committed.
+	reserved   []ecs.Entity // Reserved entities that are not materialized yet.
+}
+
+// newFuzzHarness creates a new harness for the given fuzzer data.
+func newFuzzHarness(t *testing.T, data []byte) *fuzzHarness {
+	h := &fuzzHarness{
+		t:     t,
+		input: fuzzInput{data: data},
+		world: ecs.NewWorld(4),
+		ref:   newRefWorld(),
+	}
+	h.ids = [refKinds]ecs.ID{
+		ecs.ComponentID[refCompA](&h.world),
+		ecs.ComponentID[refCompB](&h.world),
+		ecs.ComponentID[refCompC](&h.world),
+		ecs.ComponentID[refCompR](&h.world),
+	}
+	h.listener.h = h
+	h.world.SetListener(&h.listener)
+	return h
+}
+
+// Step applies a single random operation, and checks the result.
+func (h *fuzzHarness) Step() {
+	h.step++
+	in := &h.input
+	op := in.Int(15)
+
+	switch op {
+	case 0:
+		h.newEntity(in.Mask())
+	case 1:
+		if h.ref.Len() > 0 {
+			e := h.ref.Pick(in.Int(256))
+			h.world.RemoveEntity(e)
+			h.ref.RemoveEntity(e)
+		}
+	case 2, 3, 4:
+		h.exchange(op, in.Mask(), in.Mask())
+	case 5:
+		h.setValue(in.Int(refKinds), int32(in.Byte()))
+	case 6:
+		h.setTarget()
+	case 7:
+		h.newBatch(in.Mask(), 1+in.Int(8))
+	case 8:
+		h.removeBatch()
+	case 9:
+		h.exchangeBatch(in.Mask(), in.Mask())
+	case 10:
+		h.setTargetBatch()
+	case 11:
+		h.query(false)
+	case 12:
+		h.query(true)
+	case 13:
+		switch in.Int(8) {
+		case 0:
+			if h.tx == nil {
+				h.world.Reset()
+				h.ref.Reset()
+				h.reserved = h.reserved[:0]
+			}
+		case 1:
+			h.world.SetDeterministic(!h.world.IsDeterministic())
+		case 2:
+			h.transaction()
+		case 3:
+			h.reserve(in.Mask())
+		}
+	case 14:
+		h.Check()
+	}
+
+	if h.tx == nil {
+		h.checkEvents(op)
+	} else {
+		h.ref.TakeEvents()
+	}
+	if err := h.world.Validate(); err != nil {
+		h.t.Fatalf("step %d, op %d: invalid world state:\n%s", h.step, op, err)
+	}
+}
+
+// newEntity creates a single entity, optionally with a relation target.
+func (h *fuzzHarness) newEntity(comps uint8) {
+	target := h.target()
+	var e ecs.Entity
+	if comps&(1<<refR) != 0 && h.input.Bool() {
+		e = ecs.NewBuilder(&h.world, h.toIDs(comps)...).WithRelation(h.ids[refR]).New(target)
+	} else {
+		target = ecs.Entity{}
+		e = h.world.NewEntity(h.toIDs(comps)...)
+	}
+	h.addEntity(e, comps, target)
+}
+
+// reserve reserves entities, or materializes a reserved entity.
+// Does nothing during transactions, as the harness does not track rolled back materializations.
+func (h *fuzzHarness) reserve(comps uint8) {
+	if h.tx != nil {
+		return
+	}
+	if len(h.reserved) > 0 && h.input.Bool() {
+		idx := h.input.Int(len(h.reserved))
+		e := h.reserved[idx]
+		h.reserved = append(h.reserved[:idx], h.reserved[idx+1:]...)
+		h.world.Materialize(e, h.toIDs(comps)...)
+		h.addEntity(e, comps, ecs.Entity{})
+		return
+	}
+	for i := h.input.Int(4); i >= 0; i-- {
+		h.reserved = append(h.reserved, h.world.Reserve())
+	}
+}
+
+// newBatch creates a batch of entities, optionally with a relation target.
+func (h *fuzzHarness) newBatch(comps uint8, count int) {
+	target := h.target()
+	var query ecs.Query
+	if comps&(1<<refR) != 0 && h.input.Bool() {
+		query = ecs.NewBuilder(&h.world, h.toIDs(comps)...).WithRelation(h.ids[refR]).NewBatchQ(count, target)
+	} else {
+		target = ecs.Entity{}
+		query = h.world.Batch().NewQ(count, h.toIDs(comps)...)
+	}
+	entities := []ecs.Entity{}
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	if len(entities) != count {
+		h.t.Fatalf("step %d: expected %d new entities, got %d", h.step, count, len(entities))
+	}
+	for _, e := range entities {
+		h.addEntity(e, comps, target)
+	}
+}
+
+// addEntity adds an entity created by the world to the reference model.
+func (h *fuzzHarness) addEntity(e ecs.Entity, comps uint8, target ecs.Entity) {
+	if err := h.ref.NewEntity(e, comps, target); err != nil {
+		h.t.Fatalf("step %d: %s", h.step, err)
+	}
+}
+
+// exchange adds and/or removes components of a single entity.
+func (h *fuzzHarness) exchange(op int, add, rem uint8) {
+	if h.ref.Len() == 0 {
+		return
+	}
+	e := h.ref.Pick(h.input.Int(256))
+	comps := h.ref.Get(e).comps
+	add &^= comps
+	rem &= comps
+	switch op {
+	case 2:
+		if add == 0 {
+			return
+		}
+		h.world.Add(e, h.toIDs(add)...)
+		rem = 0
+	case 3:
+		if rem == 0 {
+			return
+		}
+		h.world.Remove(e, h.toIDs(rem)...)
+		add = 0
+	default:
+		if add == 0 && rem == 0 {
+			return
+		}
+		h.world.Exchange(e, h.toIDs(add), h.toIDs(rem))
+	}
+	h.ref.Exchange(e, add, rem)
+}
+
+// setValue sets a component value of an entity via a pointer.
+func (h *fuzzHarness) setValue(kind int, value int32) {
+	if h.ref.Len() == 0 || kind == refC {
+		return
+	}
+	e := h.ref.Pick(h.input.Int(256))
+	ent := h.ref.Get(e)
+	if ent.comps&(1<<kind) == 0 {
+		return
+	}
+	if h.tx != nil {
+		h.tx.Track(e)
+	}
+	ptr := h.world.Get(e, h.ids[kind])
+	switch kind {
+	case refA:
+		(*refCompA)(ptr).V = value
+	case refB:
+		(*refCompB)(ptr).V = value
+	case refR:
+		(*refCompR)(ptr).V = value
+	}
+	ent.values[kind] = value
+}
+
+// transaction applies random operations in a possibly nested transaction, and commits or rolls it back.
+// Events are not compared for transactions.
+func (h *fuzzHarness) transaction() {
+	ref := h.ref.Clone()
+	parent := h.tx
+	h.tx = h.world.Begin()
+
+	steps := h.input.Int(16)
+	for i := 0; i < steps; i++ {
+		h.Step()
+	}
+
+	if h.input.Bool() {
+		h.tx.Rollback()
+		h.ref = ref
+	} else {
+		h.committing = parent == nil
+		h.tx.Commit()
+		h.committing = false
+	}
+	h.tx = parent
+	h.Check()
+}
+
+// setTarget sets the relation target of a single entity.
+func (h *fuzzHarness) setTarget() {
+	if h.ref.Len() == 0 {
+		return
+	}
+	e := h.ref.Pick(h.input.Int(256))
+	target := h.target()
+	if h.ref.Get(e).comps&(1<<refR) == 0 {
+		return
+	}
+	h.world.Relations().Set(e, h.ids[refR], target)
+	h.ref.SetTarget(e, target)
+}
+
+// removeBatch removes all entities matching a random filter.
+func (h *fuzzHarness) removeBatch() {
+	rf, filter := h.filter(0, 0, true)
+	expected := h.ref.Query(&rf)
+	count := h.world.Batch().RemoveEntities(filter)
+	if count != len(expected) {
+		h.t.Fatalf("step %d: expected %d removed entities, got %d", h.step, len(expected), count)
+	}
+	for _, e := range expected {
+		h.ref.RemoveEntity(e)
+	}
+}
+
+// exchangeBatch adds and/or removes components of all entities matching a random filter.
+func (h *fuzzHarness) exchangeBatch(add, rem uint8) {
+	rem &^= add
+	if add == 0 && rem == 0 {
+		return
+	}
+	rf, filter := h.filter(rem, add, false)
+	expected := h.ref.Query(&rf)
+
+	var count int
+	switch {
+	case rem == 0:
+		count = h.world.Batch().Add(filter, h.toIDs(add)...)
+	case add == 0:
+		count = h.world.Batch().Remove(filter, h.toIDs(rem)...)
+	default:
+		count = h.world.Batch().Exchange(filter, h.toIDs(add), h.toIDs(rem))
+	}
+	if count != len(expected) {
+		h.t.Fatalf("step %d: expected %d changed entities, got %d", h.step, len(expected), count)
+	}
+	for _, e := range expected {
+		h.ref.Exchange(e, add, rem)
+	}
+}
+
+// setTargetBatch sets the relation target of all entities matching a random filter.
+func (h *fuzzHarness) setTargetBatch() {
+	target := h.target()
+	rf, filter := h.filter(1<<refR, 0, false)
+	expected := h.ref.Query(&rf)
+	count := h.world.Relations().SetBatch(filter, h.ids[refR], target)
+	if count != len(expected) {
+		h.t.Fatalf("step %d: expected %d changed entities, got %d", h.step, len(expected), count)
+	}
+	for _, e := range expected {
+		h.ref.SetTarget(e, target)
+	}
+}
+
+// query compares the results of a random, optionally cached, query.
+func (h *fuzzHarness) query(cached bool) {
+	rf, filter := h.filter(0, 0, true)
+	if cached {
+		cf := h.world.Cache().Register(filter)
+		defer h.world.Cache().Unregister(&cf)
+		filter = &cf
+	}
+	expected := map[ecs.Entity]bool{}
+	for _, e := range h.ref.Query(&rf) {
+		expected[e] = true
+	}
+
+	query := h.world.Query(filter)
+	if count := query.Count(); count != len(expected) {
+		query.Close()
+		h.t.Fatalf("step %d: expected query count %d, got %d for filter %+v (cached: %t)", h.step, len(expected), count, rf, cached)
+	}
+	for query.Next() {
+		e := query.Entity()
+		if !expected[e] {
+			query.Close()
+			h.t.Fatalf("step %d: query returned unexpected entity %v", h.step, e)
+		}
+		delete(expected, e)
+		ent := h.ref.Get(e)
+		for kind := 0; kind < refKinds; kind++ {
+			if ent.comps&(1<<kind) == 0 || kind == refC {
+				continue
+			}
+			if v := h.value(query.Get(h.ids[kind]), kind); v != ent.values[kind] {
+				query.Close()
+				h.t.Fatalf("step %d: query returned value %d for component %d of entity %v, expected %d",
+					h.step, v, kind, e, ent.values[kind])
+			}
+		}
+	}
+	if len(expected) > 0 {
+		h.t.Fatalf("step %d: query missed %d entities", h.step, len(expected))
+	}
+}
+
+// Check compares all entities, their components, values and relation targets.
+func (h *fuzzHarness) Check() {
+	query := h.world.Query(ecs.All())
+	count := query.Count()
+	query.Close()
+	if count != h.ref.Len() {
+		h.t.Fatalf("step %d: expected %d entities, got %d", h.step, h.ref.Len(), count)
+	}
+
+	for i := 0; i < h.ref.Len(); i++ {
+		e := h.ref.Pick(i)
+		ent := h.ref.Get(e)
+		if !h.world.Alive(e) {
+			h.t.Fatalf("step %d: entity %v is not alive", h.step, e)
+		}
+		mask := h.world.Mask(e)
+		if comps := h.kinds(&mask); comps != ent.comps {
+			h.t.Fatalf("step %d: entity %v has components %04b, expected %04b", h.step, e, comps, ent.comps)
+		}
+		for kind := 0; kind < refKinds; kind++ {
+			if ent.comps&(1<<kind) == 0 || kind == refC {
+				continue
+			}
+			if v := h.value(h.world.Get(e, h.ids[kind]), kind); v != ent.values[kind] {
+				h.t.Fatalf("step %d: entity %v has value %d for component %d, expected %d", h.step, e, v, kind, ent.values[kind])
+			}
+		}
+		if ent.comps&(1<<refR) != 0 {
+			if target := h.world.Relations().Get(e, h.ids[refR]); target != ent.target {
+				h.t.Fatalf("step %d: entity %v has relation target %v, expected %v", h.step, e, target, ent.target)
+			}
+		}
+	}
+}
+
+// checkEvents compares the events of the last operation.
+func (h *fuzzHarness) checkEvents(op int) {
+	expected := h.ref.TakeEvents()
+	actual := h.listener.events
+	h.listener.events = nil
+	sortRefEvents(actual)
+
+	if len(actual) != len(expected) {
+		h.t.Fatalf("step %d, op %d: expected %d events, got %d:\nexpected: %+v\nactual:   %+v",
+			h.step, op, len(expected), len(actual), expected, actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			h.t.Fatalf("step %d, op %d: event %d differs:\nexpected: %+v\nactual:   %+v", h.step, op, i, expected[i], actual[i])
+		}
+	}
+}
+
+// filter creates a random filter, as a reference filter and as a world filter.
+// Components in include are always included, and components in exclude are always excluded.
+func (h *fuzzHarness) filter(include, exclude uint8, relation bool) (refFilter, ecs.Filter) {
+	in := &h.input
+	relation = relation && exclude&(1<<refR) == 0 && in.Int(4) == 0
+	if relation {
+		// Relation filters are only defined for filters that include the relation component.
+		include |= 1 << refR
+	}
+	rf := refFilter{
+		include: (in.Mask() | include) &^ exclude,
+	}
+	rf.exclude = (in.Mask() &^ rf.include) | exclude
+	if in.Int(4) > 0 {
+		rf.exclude = exclude
+	}
+
+	var filter ecs.Filter
+	if rf.exclude == 0 && in.Bool() {
+		mask := ecs.All(h.toIDs(rf.include)...)
+		filter = mask
+	} else {
+		mf := ecs.All(h.toIDs(rf.include)...).Without(h.toIDs(rf.exclude)...)
+		filter = &mf
+	}
+
+	if relation {
+		rf.hasTarget = true
+		rf.target = h.target()
+		rel := ecs.NewRelationFilter(filter, rf.target)
+		filter = &rel
+	}
+	return rf, filter
+}
+
+// target picks a random alive relation target, or the zero entity.
+func (h *fuzzHarness) target() ecs.Entity {
+	index := h.input.Int(256)
+	if h.ref.Len() == 0 || index%4 == 0 {
+		return ecs.Entity{}
+	}
+	return h.ref.Pick(index)
+}
+
+// toIDs converts a bit mask of component kinds to component IDs.
+func (h *fuzzHarness) toIDs(comps uint8) []ecs.ID {
+	ids := []ecs.ID{}
+	for kind := 0; kind < refKinds; kind++ {
+		if comps&(1<<kind) != 0 {
+			ids = append(ids, h.ids[kind])
+		}
+	}
+	return ids
+}
+
+// kinds converts a component mask to a bit mask of component kinds.
+func (h *fuzzHarness) kinds(mask *ecs.Mask) uint8 {
+	var comps uint8
+	for kind := 0; kind < refKinds; kind++ {
+		if mask.Get(h.ids[kind]) {
+			comps |= 1 << kind
+		}
+	}
+	return comps
+}
+
+// value reads the value of a component via a pointer.
+func (h *fuzzHarness) value(ptr unsafe.Pointer, kind int) int32 {
+	switch kind {
+	case refA:
+		return (*refCompA)(ptr).V
+	case refB:
+		return (*refCompB)(ptr).V
+	default:
+		return (*refCompR)(ptr).V
+	}
+}
@@ -0,0 +1,181 @@
+package ecs
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// hookFn is a component lifecycle hook, with the component passed as an untyped pointer.
+type hookFn func(w *World, entity Entity, comp unsafe.Pointer)
+
+// hookKind is the kind of a component lifecycle hook.
+type hookKind uint8
+
+const (
+	hookAdd    hookKind = iota // Called after a component was added.
+	hookRemove                 // Called before a component is removed.
+	hookSet                    // Called after a component was set.
+	hookKinds                  // Number of hook kinds.
+)
+
+// componentHooks holds the lifecycle hooks of component types. See [RegisterHooks].
+type componentHooks struct {
+	funcs      [][hookKinds]hookFn // Hooks by component ID. Nil if no hooks are registered.
+	masks      [hookKinds]Mask     // Components that have a hook, per hook kind.
+	registered Mask                // Components that have hooks registered.
+}
+
+// RegisterHooks registers lifecycle hooks for a component type, and returns the type's [ID].
+// Registers the type if it is not already registered.
+//
+// Hooks are intended for component types that own external resources and require setup and teardown.
+// Each hook receives the world, the affected entity and a pointer to the component.
+// Nil hooks are ignored.
+//
+//   - onAdd is called after the component was added to an entity, and after component values were assigned.
+//     This includes entity creation, [World.Add], [World.Exchange], [Builder], [Batch] operations and the generic API.
+//   - onRemove is called before the component is removed from an entity.
+//     This includes [World.Remove], [World.Exchange], [World.RemoveEntity] and [Batch] operations.
+//   - onSet is called after the component was overwritten via [World.Set] or the generic Map.Set,
+//     or when notified explicitly via [World.NotifySet].
+//
+// For a single entity, hooks are called in the order of the component IDs passed to the operation.
+// For batch operations, hooks are called entity by entity.
+// For batch operations that return a query, like [Batch.NewQ], hooks are called before the query is returned.
+// OnAdd hooks are called before listeners are notified, and OnRemove hooks after.
+//
+// Hooks are called with the world locked, so they must not create or remove entities,
+// or add or remove components. They may read and modify component values.
+//
+// Hooks are not called by [World.Reset], [World.LoadEntities] and the rollback of transactions (see [World.Begin]).
+// Hooks are not applied to entities that already have the component when the hooks are registered.
+//
+// Panics if hooks are already registered for the component type.
+func RegisterHooks[T any](w *World, onAdd, onRemove, onSet func(w *World, entity Entity, comp *T)) ID {
+	id := ComponentID[T](w)
+
+	var funcs [hookKinds]hookFn
+	if onAdd != nil {
+		funcs[hookAdd] = func(w *World, entity Entity, comp unsafe.Pointer) { onAdd(w, entity, (*T)(comp)) }
+	}
+	if onRemove != nil {
+		funcs[hookRemove] = func(w *World, entity Entity, comp unsafe.Pointer) { onRemove(w, entity, (*T)(comp)) }
+	}
+	if onSet != nil {
+		funcs[hookSet] = func(w *World, entity Entity, comp unsafe.Pointer) { onSet(w, entity, (*T)(comp)) }
+	}
+
+	if w.hooks.registered.Get(id) {
+		panic(fmt.Sprintf("hooks for component type %v are already registered", w.registry.Types[id.id]))
+	}
+	w.hooks.register(id, funcs)
+	return id
+}
+
+// NotifySet calls the OnSet hook of a component of an [Entity], if any. See [RegisterHooks].
+//
+// Use it after modifying a component via a pointer, if the component type has an OnSet hook.
+// [World.Set] and the generic Map.Set call it automatically.
+//
+// Panics when called for a removed (and potentially recycled) entity,
+// or if the entity does not have a component of that type.
+func (w *World) NotifySet(entity Entity, comp ID) {
+	if !w.entityPool.Alive(entity) {
+		panic("can't notify set for a dead entity")
+	}
+	if !w.entities[entity.id].arch.HasComponent(comp) {
+		panic(fmt.Sprintf("entity does not have a component of type %v, can't notify set", w.registry.Types[comp.id]))
+	}
+	w.callHooks(hookSet, entity, []ID{comp})
+}
+
+// NotifySetUnchecked calls the OnSet hook of a component of an [Entity], if any. See [RegisterHooks].
+//
+// NotifySetUnchecked is an optimized version of [World.NotifySet],
+// for cases where entities are static or checked with [World.Alive] in user code.
+// It has almost no cost if the component type has no OnSet hook.
+// Does not check whether the entity has the component.
+func (w *World) NotifySetUnchecked(entity Entity, comp ID) {
+	if w.hooks.funcs != nil && w.hooks.funcs[comp.id][hookSet] != nil {
+		// outline to allow inlining of the fast path
+		w.callSetHook(entity, comp)
+	}
+}
+
+// callSetHook calls the OnSet hook of a component of an entity.
+// The world is locked while the hook is running.
+func (w *World) callSetHook(entity Entity, comp ID) {
+	lock := w.lock()
+	index := &w.entities[entity.id]
+	w.hooks.funcs[comp.id][hookSet](w, entity, index.arch.Get(index.index, comp))
+	w.unlock(lock)
+}
+
+// register sets the hooks of a component.
+func (h *componentHooks) register(id ID, funcs [hookKinds]hookFn) {
+	if h.funcs == nil {
+		h.funcs = make([][hookKinds]hookFn, MaskTotalBits)
+	}
+	h.funcs[id.id] = funcs
+	h.registered.Set(id, true)
+	for kind, fn := range funcs {
+		if fn != nil {
+			h.masks[kind].Set(id, true)
+		}
+	}
+}
+
+// callHooks calls the hooks of the given kind for components of a single entity.
+// The world is locked while hooks are running.
+func (w *World) callHooks(kind hookKind, entity Entity, comps []ID) {
+	mask := &w.hooks.masks[kind]
+	if mask.IsZero() {
+		return
+	}
+	var lock uint8
+	locked := false
+	for _, id := range comps {
+		if !mask.Get(id) {
+			continue
+		}
+		if !locked {
+			lock = w.lock()
+			locked = true
+		}
+		index := &w.entities[entity.id]
+		w.hooks.funcs[id.id][kind](w, entity, index.arch.Get(index.index, id))
+	}
+	if locked {
+		w.unlock(lock)
+	}
+}
+
+// callHooksBatch calls the hooks of the given kind for components of a range of entities in an archetype.
+// The world is locked while hooks are running.
+func (w *World) callHooksBatch(kind hookKind, arch *archetype, start, end uint32, comps []ID) {
+	mask := &w.hooks.masks[kind]
+	if mask.IsZero() {
+		return
+	}
+	hooked := false
+	for _, id := range comps {
+		if mask.Get(id) {
+			hooked = true
+			break
+		}
+	}
+	if !hooked {
+		return
+	}
+
+	lock := w.lock()
+	for i := start; i < end; i++ {
+		entity := arch.GetEntity(i)
+		for _, id := range comps {
+			if mask.Get(id) {
+				w.hooks.funcs[id.id][kind](w, entity, arch.Get(i, id))
+			}
+		}
+	}
+	w.unlock(lock)
+}
@@ -0,0 +1,150 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hookRecorder records calls of component lifecycle hooks.
+type hookRecorder struct {
+	calls []string
+}
+
+func (r *hookRecorder) register(w *World) ID {
+	return RegisterHooks[Position](w,
+		func(w *World, entity Entity, pos *Position) {
+			r.calls = append(r.calls, fmt.Sprintf("add %d %d", entity.id, pos.X))
+			pos.Y = 1
+		},
+		func(w *World, entity Entity, pos *Position) {
+			r.calls = append(r.calls, fmt.Sprintf("remove %d %d", entity.id, pos.X))
+		},
+		func(w *World, entity Entity, pos *Position) {
+			r.calls = append(r.calls, fmt.Sprintf("set %d %d", entity.id, pos.X))
+		},
+	)
+}
+
+func (r *hookRecorder) take() []string {
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+func TestRegisterHooks(t *testing.T) {
+	w := NewWorld()
+	rec := hookRecorder{}
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	posID := rec.register(&w)
+	assert.Equal(t, posID, ComponentID[Position](&w))
+
+	assert.PanicsWithValue(t, "hooks for component type ecs.Position are already registered",
+		func() { RegisterHooks[Position](&w, nil, nil, nil) })
+
+	e1 := w.NewEntity(posID, velID)
+	assert.Equal(t, []string{"add 1 0"}, rec.take())
+	assert.Equal(t, 1, (*Position)(w.Get(e1, posID)).Y)
+
+	e2 := w.NewEntityWith(Component{ID: posID, Comp: &Position{X: 2}})
+	assert.Equal(t, []string{"add 2 2"}, rec.take())
+
+	e3 := w.NewEntity(velID)
+	assert.Empty(t, rec.take())
+
+	w.Add(e3, posID)
+	assert.Equal(t, []string{"add 3 0"}, rec.take())
+	w.Set(e3, posID, &Position{X: 3})
+	assert.Equal(t, []string{"set 3 3"}, rec.take())
+	(*Position)(w.Get(e3, posID)).X = 4
+	w.NotifySet(e3, posID)
+	assert.Equal(t, []string{"set 3 4"}, rec.take())
+	w.NotifySetUnchecked(e3, posID)
+	assert.Equal(t, []string{"set 3 4"}, rec.take())
+	w.NotifySetUnchecked(e3, velID)
+	assert.Empty(t, rec.take())
+
+	w.Exchange(e3, []ID{relID}, []ID{posID})
+	assert.Equal(t, []string{"remove 3 4"}, rec.take())
+	w.Remove(e3, velID)
+	assert.Empty(t, rec.take())
+
+	w.Assign(e3, Component{ID: posID, Comp: &Position{X: 5}})
+	assert.Equal(t, []string{"add 3 5"}, rec.take())
+
+	e4 := NewBuilder(&w, posID, relID).WithRelation(relID).New(e1)
+	assert.Equal(t, []string{"add 4 0"}, rec.take())
+	w.Relations().Set(e4, relID, e2)
+	assert.Empty(t, rec.take())
+
+	w.RemoveEntity(e1)
+	assert.Equal(t, []string{"remove 1 0"}, rec.take())
+
+	r := w.Reserve()
+	w.Materialize(r, posID)
+	assert.Equal(t, []string{fmt.Sprintf("add %d 0", r.id)}, rec.take())
+
+	assert.PanicsWithValue(t, "can't notify set for a dead entity", func() { w.NotifySet(e1, posID) })
+	assert.PanicsWithValue(t, "entity does not have a component of type ecs.Velocity, can't notify set",
+		func() { w.NotifySet(e2, velID) })
+
+	w.Reset()
+	assert.Empty(t, rec.take())
+	assert.NoError(t, w.Validate())
+}
+
+func TestRegisterHooksBatch(t *testing.T) {
+	w := NewWorld()
+	rec := hookRecorder{}
+	velID := ComponentID[Velocity](&w)
+	posID := rec.register(&w)
+
+	w.Batch().New(2, posID)
+	assert.Equal(t, []string{"add 1 0", "add 2 0"}, rec.take())
+
+	query := w.Batch().NewQ(2, velID)
+	query.Close()
+	assert.Empty(t, rec.take())
+
+	w.Batch().Add(All(velID), posID)
+	assert.Equal(t, []string{"add 3 0", "add 4 0"}, rec.take())
+
+	w.Batch().Remove(All(velID), posID)
+	assert.Equal(t, []string{"remove 3 0", "remove 4 0"}, rec.take())
+
+	builder := NewBuilderWith(&w, Component{ID: posID, Comp: &Position{X: 7}})
+	builder.NewBatch(2)
+	assert.Equal(t, []string{"add 5 7", "add 6 7"}, rec.take())
+
+	w.Batch().RemoveEntities(All(posID))
+	assert.Equal(t, []string{"remove 1 0", "remove 2 0", "remove 5 7", "remove 6 7"}, rec.take())
+	assert.NoError(t, w.Validate())
+}
+
+func TestRegisterHooksLocked(t *testing.T) {
+	w := NewWorld()
+	velID := ComponentID[Velocity](&w)
+
+	locked := []bool{}
+	posID := RegisterHooks[Position](&w,
+		func(w *World, entity Entity, pos *Position) {
+			locked = append(locked, w.IsLocked())
+			assert.True(t, w.Has(entity, velID))
+		},
+		func(w *World, entity Entity, pos *Position) {
+			locked = append(locked, w.IsLocked())
+			assert.True(t, w.Alive(entity))
+			assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.RemoveEntity(entity) })
+		},
+		nil,
+	)
+
+	e := w.NewEntity(posID, velID)
+	w.Set(e, posID, &Position{})
+	w.RemoveEntity(e)
+	assert.Equal(t, []bool{true, true}, locked)
+	assert.False(t, w.IsLocked())
+	assert.NoError(t, w.Validate())
+}
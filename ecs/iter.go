@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package ecs
+
+import "iter"
+
+// Entities returns an iterator over all entities matching the given [Filter].
+//
+// The iterator creates a [Query] when iteration starts, and thus locks the world during iteration.
+// In contrast to manual query iteration, the query is closed automatically when breaking out of
+// the loop early, or when the loop body panics.
+//
+//	for entity := range world.Entities(&filter) {
+//		pos := (*Position)(world.Get(entity, posID))
+//		// ...
+//	}
+//
+// Requires Go 1.23 or later.
+// Compared to manual iteration with [Query.Next], the iterator has a small overhead
+// of one function call per entity. This is the cost of the automatic closing on panics.
+// See also the generic variants like [github.com/mlange-42/arche/generic.Filter1.Iter].
+func (w *World) Entities(filter Filter) iter.Seq[Entity] {
+	return func(yield func(Entity) bool) {
+		query := w.Query(filter)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity()) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the entities of the query.
+//
+// The query is iterated when the iterator is used in a range loop.
+// In contrast to manual iteration using [Query.Next], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+//	query := world.Query(&filter)
+//	for entity := range query.Iter() {
+//		pos := (*Position)(query.Get(posID))
+//		// ...
+//	}
+//
+// As with [Query.Next], a query can be iterated only once.
+//
+// Requires Go 1.23 or later.
+// See [World.Entities] for performance considerations.
+func (q *Query) Iter() iter.Seq[Entity] {
+	return func(yield func(Entity) bool) {
+		open := true
+		defer func() {
+			if open {
+				q.Close()
+			}
+		}()
+		for q.Next() {
+			if !yield(q.Entity()) {
+				return
+			}
+		}
+		open = false
+	}
+}
@@ -0,0 +1,108 @@
+//go:build go1.23
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldEntities(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	w.Batch().New(10, posID)
+	w.Batch().New(10, posID, velID)
+
+	filter := All(posID)
+	cnt := 0
+	for e := range w.Entities(&filter) {
+		assert.True(t, w.Has(e, posID))
+		assert.True(t, w.IsLocked())
+		cnt++
+	}
+	assert.Equal(t, 20, cnt)
+	assert.False(t, w.IsLocked())
+
+	cnt = 0
+	for range w.Entities(&filter) {
+		cnt++
+		if cnt == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, cnt)
+	assert.False(t, w.IsLocked())
+
+	assert.Panics(t, func() {
+		for range w.Entities(&filter) {
+			panic("test")
+		}
+	})
+	assert.False(t, w.IsLocked())
+
+	w.Batch().RemoveEntities(&filter)
+	for range w.Entities(&filter) {
+		assert.Fail(t, "no entities expected")
+	}
+	assert.False(t, w.IsLocked())
+}
+
+func TestQueryIter(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	w.Batch().New(10, posID)
+
+	filter := All(posID)
+	query := w.Query(&filter)
+	cnt := 0
+	for e := range query.Iter() {
+		assert.Equal(t, e, query.Entity())
+		pos := (*Position)(query.Get(posID))
+		pos.X = cnt
+		cnt++
+	}
+	assert.Equal(t, 10, cnt)
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(&filter)
+	for range query.Iter() {
+		break
+	}
+	assert.False(t, w.IsLocked())
+}
+
+func BenchmarkWorldEntities_1000(b *testing.B) {
+	b.StopTimer()
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	w.Batch().New(1000, posID)
+	filter := All(posID)
+	b.StartTimer()
+
+	for i := 0; i < b.N; i++ {
+		for e := range w.Entities(&filter) {
+			_ = e
+		}
+	}
+}
+
+func BenchmarkQueryIter_1000(b *testing.B) {
+	b.StopTimer()
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	w.Batch().New(1000, posID)
+	filter := All(posID)
+	b.StartTimer()
+
+	for i := 0; i < b.N; i++ {
+		query := w.Query(&filter)
+		for range query.Iter() {
+			pos := (*Position)(query.Get(posID))
+			pos.X++
+		}
+	}
+}
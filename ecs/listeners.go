@@ -0,0 +1,223 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/event"
+
+// ListenerHandle identifies a [Listener] added with [World.AddListener].
+// Use it to remove the listener with [World.RemoveListener].
+type ListenerHandle struct {
+	id uint32 // Unique ID of the listener. Zero is reserved for the listener set via World.SetListener.
+}
+
+// listenerEntry is a listener registered in a worldListener.
+type listenerEntry struct {
+	listener Listener // The listener.
+	priority int      // Priority of the listener. Higher priorities are notified first.
+	id       uint32   // Unique ID of the listener.
+}
+
+// worldListener is the internal listener of a [World] while there are
+// multiple listeners, or per-entity watches.
+//
+// Dispatches events to listeners and watches, and manages subscriptions
+// based on the union of their subscriptions, like [github.com/mlange-42/arche/listener.Dispatch].
+type worldListener struct {
+	listeners     []listenerEntry    // Listeners, sorted by descending priority.
+	entities      map[Entity][]watch // Watches by entity.
+	targets       map[Entity][]watch // Watches by relation target.
+	watchCount    int                // Number of active watches.
+	nextID        uint32             // ID of the next listener.
+	nextWatch     uint32             // ID of the next watch.
+	events        event.Subscription // Union of subscribed event types of listeners.
+	watchEvents   event.Subscription // Union of subscribed event types of watches.
+	components    Mask               // Union of subscribed components of listeners.
+	hasComponents bool               // Whether there is a restriction to components.
+}
+
+// newWorldListener creates a new worldListener.
+func newWorldListener() worldListener {
+	return worldListener{
+		entities: map[Entity][]watch{},
+		targets:  map[Entity][]watch{},
+		nextID:   1,
+	}
+}
+
+// Notify the listener.
+func (l *worldListener) Notify(world *World, evt EntityEvent) {
+	listeners := l.listeners
+	for i := range listeners {
+		ls := listeners[i].listener
+		trigger := ls.Subscriptions() & evt.EventTypes
+		if trigger != 0 && subscribes(trigger, &evt.Added, &evt.Removed, ls.Components(), evt.OldRelation, evt.NewRelation) {
+			ls.Notify(world, evt)
+		}
+	}
+	if l.watchCount > 0 {
+		l.notifyWatches(world, &evt)
+	}
+}
+
+// NotifyBatch notifies the listener about a batch event.
+//
+// Forwards the event to listeners implementing [BatchListener],
+// and falls back to per-entity events for other listeners and for watches.
+func (l *worldListener) NotifyBatch(world *World, evt BatchEvent) {
+	listeners := l.listeners
+	for i := range listeners {
+		ls := listeners[i].listener
+		trigger := ls.Subscriptions() & evt.EventTypes
+		if trigger == 0 || !subscribes(trigger, &evt.Added, &evt.Removed, ls.Components(), evt.OldRelation, evt.NewRelation) {
+			continue
+		}
+		if bl, ok := ls.(BatchListener); ok {
+			bl.NotifyBatch(world, evt)
+			continue
+		}
+		for j := 0; j < evt.Len(); j++ {
+			ls.Notify(world, evt.Event(j))
+		}
+	}
+	if l.watchCount > 0 {
+		for j := 0; j < evt.Len(); j++ {
+			e := evt.Event(j)
+			l.notifyWatches(world, &e)
+		}
+	}
+}
+
+// Subscriptions of the listener.
+func (l *worldListener) Subscriptions() event.Subscription {
+	if l.watchCount > 0 {
+		return l.events | l.watchEvents | event.EntityRemoved
+	}
+	return l.events
+}
+
+// Components the listener subscribes to.
+// Watches are not considered here, see [worldListener.watched].
+func (l *worldListener) Components() *Mask {
+	if l.hasComponents {
+		return &l.components
+	}
+	return nil
+}
+
+// Add a listener with the given ID and priority.
+func (l *worldListener) Add(listener Listener, id uint32, priority int) {
+	idx := len(l.listeners)
+	for i := range l.listeners {
+		if l.listeners[i].priority < priority {
+			idx = i
+			break
+		}
+	}
+	// Copy, as the list may currently be iterated by Notify.
+	listeners := make([]listenerEntry, 0, len(l.listeners)+1)
+	listeners = append(listeners, l.listeners[:idx]...)
+	listeners = append(listeners, listenerEntry{listener: listener, priority: priority, id: id})
+	l.listeners = append(listeners, l.listeners[idx:]...)
+	l.updateSubscriptions()
+}
+
+// Set replaces the listener with the given ID in its current position, or adds it if it is not present.
+func (l *worldListener) Set(listener Listener, id uint32, priority int) {
+	for i := range l.listeners {
+		if l.listeners[i].id != id {
+			continue
+		}
+		// Copy, as the list may currently be iterated by Notify.
+		listeners := append([]listenerEntry{}, l.listeners...)
+		listeners[i].listener = listener
+		l.listeners = listeners
+		l.updateSubscriptions()
+		return
+	}
+	l.Add(listener, id, priority)
+}
+
+// Remove a listener by ID. Returns whether it was found.
+func (l *worldListener) Remove(id uint32) bool {
+	for i := range l.listeners {
+		if l.listeners[i].id != id {
+			continue
+		}
+		// Copy, as the list may currently be iterated by Notify.
+		listeners := make([]listenerEntry, 0, len(l.listeners)-1)
+		listeners = append(listeners, l.listeners[:i]...)
+		l.listeners = append(listeners, l.listeners[i+1:]...)
+		l.updateSubscriptions()
+		return true
+	}
+	return false
+}
+
+// updateSubscriptions updates the union of subscribed event types and components.
+func (l *worldListener) updateSubscriptions() {
+	l.events = 0
+	l.components = Mask{}
+	l.hasComponents = true
+	for i := range l.listeners {
+		ls := l.listeners[i].listener
+		l.events |= ls.Subscriptions()
+		if cmp := ls.Components(); cmp == nil {
+			l.hasComponents = false
+		} else {
+			l.components = l.components.Or(cmp)
+		}
+	}
+}
+
+// AddListener adds a [Listener] to the world, in addition to other listeners.
+// Returns a handle that can be used to remove the listener with [World.RemoveListener].
+//
+// The optional priority determines the order of notification. Listeners with higher priority are notified first.
+// Listeners with equal priority are notified in the order they were added.
+// The default priority, also of the listener set with [World.SetListener], is zero.
+//
+// Listeners should not alter their subscriptions or components after being added.
+// Events are only processed if at least one listener subscribes to them.
+//
+// For details, see [EntityEvent], [Listener] and sub-package [event].
+func (w *World) AddListener(listener Listener, priority ...int) ListenerHandle {
+	if listener == nil {
+		panic("can't add a nil listener")
+	}
+	prio := 0
+	if len(priority) > 0 {
+		prio = priority[0]
+	}
+	id := w.listeners.nextID
+	w.listeners.nextID++
+	w.listeners.Add(listener, id, prio)
+	w.updateListener()
+	return ListenerHandle{id: id}
+}
+
+// RemoveListener removes a [Listener] that was added with [World.AddListener].
+// Does nothing if the listener was already removed.
+func (w *World) RemoveListener(handle ListenerHandle) {
+	if handle.id == 0 {
+		return
+	}
+	if w.listeners.Remove(handle.id) {
+		w.updateListener()
+	}
+}
+
+// updateListener sets the effective listener of the world.
+//
+// Listeners are notified directly if there is only one, and via the dispatching worldListener otherwise.
+func (w *World) updateListener() {
+	var listener Listener
+	switch {
+	case w.listeners.watchCount > 0 || len(w.listeners.listeners) > 1:
+		listener = &w.listeners
+	case len(w.listeners.listeners) == 1:
+		listener = w.listeners.listeners[0].listener
+	}
+	if w.tx != nil && listener != nil {
+		w.txListener.listener = listener
+		listener = &w.txListener
+	}
+	w.listener = listener
+}
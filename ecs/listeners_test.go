@@ -0,0 +1,160 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderListener struct {
+	name       string
+	order      *[]string
+	subs       event.Subscription
+	components *Mask
+}
+
+func (l *orderListener) Notify(world *World, e EntityEvent) {
+	*l.order = append(*l.order, l.name)
+}
+
+func (l *orderListener) Subscriptions() event.Subscription { return l.subs }
+
+func (l *orderListener) Components() *Mask { return l.components }
+
+func TestWorldAddListener(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	order := []string{}
+	posMask := All(posID)
+	velMask := All(velID)
+	l1 := orderListener{name: "l1", order: &order, subs: event.All, components: &posMask}
+	l2 := orderListener{name: "l2", order: &order, subs: event.EntityCreated, components: &velMask}
+	l3 := orderListener{name: "l3", order: &order, subs: event.ComponentAdded}
+	main := orderListener{name: "main", order: &order, subs: event.All}
+
+	h1 := w.AddListener(&l1)
+	assert.Equal(t, &l1, w.listener)
+
+	h2 := w.AddListener(&l2, 10)
+	assert.Equal(t, &w.listeners, w.listener)
+	assert.Equal(t, event.All, w.listener.Subscriptions())
+	assert.Equal(t, All(posID, velID), *w.listener.Components())
+
+	w.SetListener(&main)
+	w.AddListener(&l3, -1)
+	assert.Nil(t, w.listener.Components())
+
+	w.NewEntity(posID, velID)
+	assert.Equal(t, []string{"l2", "l1", "main", "l3"}, order)
+
+	order = order[:0]
+	e := w.NewEntity(posID)
+	assert.Equal(t, []string{"l1", "main", "l3"}, order)
+
+	order = order[:0]
+	w.RemoveListener(h2)
+	w.RemoveListener(h2)
+	w.RemoveListener(ListenerHandle{})
+	w.SetListener(nil)
+	w.Add(e, velID)
+	assert.Equal(t, []string{"l3"}, order)
+
+	order = order[:0]
+	w.RemoveListener(h1)
+	w.RemoveEntity(e)
+	assert.Equal(t, []string{}, order)
+	assert.Equal(t, &l3, w.listener)
+
+	assert.Panics(t, func() { w.AddListener(nil) })
+}
+
+func TestWorldSetListenerOrder(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	order := []string{}
+	main1 := orderListener{name: "main1", order: &order, subs: event.All}
+	main2 := orderListener{name: "main2", order: &order, subs: event.EntityCreated}
+	l1 := orderListener{name: "l1", order: &order, subs: event.All}
+
+	w.SetListener(&main1)
+	w.AddListener(&l1)
+	w.SetListener(&main2)
+	assert.Equal(t, event.All, w.listener.Subscriptions())
+
+	w.NewEntity(posID)
+	assert.Equal(t, []string{"main2", "l1"}, order)
+
+	order = order[:0]
+	w.SetListener(nil)
+	w.SetListener(&main1)
+	w.NewEntity(posID)
+	assert.Equal(t, []string{"l1", "main1"}, order)
+}
+
+func TestWorldRemoveListenerInCallback(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	count := 0
+	var h ListenerHandle
+	ls := newTestListener(func(world *World, e EntityEvent) {
+		count++
+		world.RemoveListener(h)
+	})
+	ls2 := newTestListener(func(world *World, e EntityEvent) {
+		count++
+	})
+	h = w.AddListener(&ls)
+	w.AddListener(&ls2)
+
+	w.NewEntity(posID)
+	assert.Equal(t, 2, count)
+	w.NewEntity(posID)
+	assert.Equal(t, 3, count)
+}
+
+func TestWorldListenersWatch(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	order := []string{}
+	posMask := All(posID)
+	l1 := orderListener{name: "l1", order: &order, subs: event.ComponentAdded, components: &posMask}
+	w.AddListener(&l1)
+
+	e := w.NewEntity()
+	h := w.Watch(e, event.ComponentAdded, func(world *World, evt EntityEvent) {
+		order = append(order, "watch")
+	})
+	assert.Equal(t, event.ComponentAdded|event.EntityRemoved, w.listener.Subscriptions())
+	assert.Equal(t, &posMask, w.listener.Components())
+
+	w.Add(e, posID)
+	assert.Equal(t, []string{"l1", "watch"}, order)
+
+	order = order[:0]
+	w.Add(e, velID)
+	w.Batch().New(5, velID)
+	assert.Equal(t, []string{"watch"}, order)
+
+	order = order[:0]
+	parent := w.NewEntity()
+	w.WatchTarget(parent, event.EntityCreated|event.TargetChanged, func(world *World, evt EntityEvent) {
+		order = append(order, "target")
+	})
+	NewBuilder(&w, relID).WithRelation(relID).NewBatch(2, parent)
+	child := w.NewEntity(relID)
+	w.Relations().Set(child, relID, parent)
+	assert.Equal(t, []string{"target", "target", "target"}, order)
+
+	order = order[:0]
+	w.RemoveEntity(parent)
+	w.Unwatch(h)
+	assert.Equal(t, &l1, w.listener)
+}
@@ -0,0 +1,106 @@
+package ecs
+
+import "fmt"
+
+// entityNames stores unique names of entities.
+type entityNames struct {
+	byName   map[string]Entity // Mapping from names to entities.
+	byEntity map[eid]string    // Mapping from entity IDs to names.
+}
+
+// SetName sets a unique name for an entity, e.g. for use in scenario files or debugging output.
+// Replaces the previous name of the entity, if any.
+// An empty name removes the entity's name.
+//
+// Names are removed automatically when their entities are removed.
+// They are included in [World.DumpEntities] and [World.LoadEntities],
+// and counted in [stats.Entities].
+//
+// Panics if the entity is dead, or if the name is already used by another entity.
+func (w *World) SetName(entity Entity, name string) {
+	if !w.entityPool.Alive(entity) {
+		panic("can't set the name of a dead entity")
+	}
+	if name == "" {
+		w.removeName(entity.id)
+		return
+	}
+	if other, ok := w.names.byName[name]; ok {
+		if other == entity {
+			return
+		}
+		panic(fmt.Sprintf("name '%s' is already used by entity %v", name, other))
+	}
+
+	if w.tx != nil {
+		w.tx.captureNames()
+	}
+	if w.names.byName == nil {
+		w.names.byName = map[string]Entity{}
+		w.names.byEntity = map[eid]string{}
+	}
+	if old, ok := w.names.byEntity[entity.id]; ok {
+		delete(w.names.byName, old)
+	}
+	w.names.byName[name] = entity
+	w.names.byEntity[entity.id] = name
+}
+
+// Name returns the name of an entity.
+// Returns an empty string if the entity has no name, or if it is dead.
+//
+// See [World.SetName].
+func (w *World) Name(entity Entity) string {
+	if !w.entityPool.Alive(entity) {
+		return ""
+	}
+	return w.names.byEntity[entity.id]
+}
+
+// Lookup returns the entity with the given name.
+// The second return value is false if there is no entity with that name.
+//
+// See [World.SetName].
+func (w *World) Lookup(name string) (Entity, bool) {
+	entity, ok := w.names.byName[name]
+	return entity, ok
+}
+
+// removeName removes the name of the entity with the given ID, if any.
+func (w *World) removeName(id eid) {
+	name, ok := w.names.byEntity[id]
+	if !ok {
+		return
+	}
+	if w.tx != nil {
+		w.tx.captureNames()
+	}
+	delete(w.names.byName, name)
+	delete(w.names.byEntity, id)
+}
+
+// Len returns the number of named entities.
+func (n *entityNames) Len() int {
+	return len(n.byEntity)
+}
+
+// Reset removes all names.
+func (n *entityNames) Reset() {
+	clear(n.byName)
+	clear(n.byEntity)
+}
+
+// Clone returns a deep copy of the names.
+func (n *entityNames) Clone() entityNames {
+	c := entityNames{
+		byName:   make(map[string]Entity, len(n.byName)),
+		byEntity: make(map[eid]string, len(n.byEntity)),
+	}
+	for name, entity := range n.byName {
+		c.byName[name] = entity
+	}
+	for id, name := range n.byEntity {
+		c.byEntity[id] = name
+	}
+	return c
+}
@@ -0,0 +1,131 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldNames(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+
+	_, ok := w.Lookup("player")
+	assert.False(t, ok)
+	assert.Equal(t, "", w.Name(e1))
+
+	w.SetName(e1, "player")
+	w.SetName(e2, "depot-3")
+	w.SetName(e1, "player")
+	assert.Equal(t, "player", w.Name(e1))
+	assert.Equal(t, 2, w.Stats().Entities.Named)
+
+	e, ok := w.Lookup("player")
+	assert.True(t, ok)
+	assert.Equal(t, e1, e)
+
+	assert.PanicsWithValue(t, "name 'player' is already used by entity {1 0}", func() { w.SetName(e2, "player") })
+
+	w.SetName(e1, "hero")
+	_, ok = w.Lookup("player")
+	assert.False(t, ok)
+	e, _ = w.Lookup("hero")
+	assert.Equal(t, e1, e)
+
+	w.SetName(e2, "player")
+	w.SetName(e2, "")
+	assert.Equal(t, "", w.Name(e2))
+	_, ok = w.Lookup("player")
+	assert.False(t, ok)
+	assert.NoError(t, w.Validate())
+
+	w.RemoveEntity(e1)
+	_, ok = w.Lookup("hero")
+	assert.False(t, ok)
+	assert.Equal(t, "", w.Name(e1))
+	assert.PanicsWithValue(t, "can't set the name of a dead entity", func() { w.SetName(e1, "hero") })
+
+	e3 := w.NewEntity(posID)
+	assert.Equal(t, e1.id, e3.id)
+	assert.Equal(t, "", w.Name(e3))
+	assert.Equal(t, 0, w.Stats().Entities.Named)
+
+	w.SetName(e2, "a")
+	w.SetName(e3, "b")
+	w.Batch().RemoveEntities(All(posID))
+	_, ok = w.Lookup("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, w.Stats().Entities.Named)
+	assert.NoError(t, w.Validate())
+
+	e4 := w.NewEntity()
+	w.SetName(e4, "c")
+	w.Reset()
+	_, ok = w.Lookup("c")
+	assert.False(t, ok)
+	assert.Equal(t, 0, w.Stats().Entities.Named)
+}
+
+func TestWorldNamesDump(t *testing.T) {
+	w := NewWorld()
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	e3 := w.NewEntity()
+	w.SetName(e1, "first")
+	w.SetName(e3, "third")
+	w.RemoveEntity(e2)
+
+	dump := w.DumpEntities()
+	assert.Equal(t, map[uint32]string{1: "first", 3: "third"}, dump.Names)
+
+	w2 := NewWorld()
+	w2.LoadEntities(&dump)
+	e, ok := w2.Lookup("third")
+	assert.True(t, ok)
+	assert.Equal(t, e3, e)
+	assert.Equal(t, "first", w2.Name(e1))
+	assert.NoError(t, w2.Validate())
+
+	w3 := NewWorld()
+	assert.Nil(t, w3.DumpEntities().Names)
+
+	dump.Names[2] = "removed"
+	assert.PanicsWithValue(t, "can't load the name of a dead entity", func() { w3.LoadEntities(&dump) })
+}
+
+func TestWorldNamesTx(t *testing.T) {
+	w := NewWorld()
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	w.SetName(e1, "first")
+
+	tx := w.Begin()
+	w.SetName(e2, "second")
+	w.RemoveEntity(e1)
+	e3 := w.NewEntity()
+	w.SetName(e3, "third")
+	tx.Rollback()
+
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, "first", w.Name(e1))
+	assert.Equal(t, "", w.Name(e2))
+	_, ok := w.Lookup("third")
+	assert.False(t, ok)
+
+	outer := w.Begin()
+	inner := w.Begin()
+	w.SetName(e1, "renamed")
+	inner.Commit()
+	assert.Equal(t, "renamed", w.Name(e1))
+	outer.Rollback()
+	assert.Equal(t, "first", w.Name(e1))
+
+	tx = w.Begin()
+	w.SetName(e2, "second")
+	tx.Commit()
+	assert.Equal(t, "second", w.Name(e2))
+	assert.NoError(t, w.Validate())
+}
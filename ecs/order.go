@@ -0,0 +1,121 @@
+package ecs
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SetDeterministic sets whether the world guarantees a well-defined iteration order.
+//
+// In deterministic mode, queries and batch operations iterate archetypes
+// ordered by their component IDs (lexicographically), then by their relation target (by ID, then generation).
+// Within an archetype, entities keep the order in which they were added,
+// as removal preserves the order of the remaining entities.
+// This makes iteration order, and thus simulation results,
+// independent of the order in which archetypes happened to be created.
+//
+// Costs of deterministic mode:
+//   - Removing an entity or changing its components or relation target
+//     is O(n) in the number of entities in its archetype, instead of O(1).
+//   - Creating and removing archetypes is O(a) in the number of archetypes,
+//     plus the number of cached filters.
+//
+// Note that the order still depends on the order in which component types are registered,
+// as this determines their IDs.
+//
+// Enabling the mode sorts existing archetypes, but does not re-order entities within archetypes.
+// For a fully reproducible order, enable it before creating any entities.
+//
+// Panics when called on a locked world.
+func (w *World) SetDeterministic(deterministic bool) {
+	w.checkLocked()
+
+	w.config.deterministic = deterministic
+	len := w.nodes.Len()
+	var i int32
+	for i = 0; i < len; i++ {
+		w.nodes.Get(i).SetDeterministic(deterministic)
+	}
+	if deterministic {
+		slices.SortFunc(w.nodePointers, func(a, b *archNode) int {
+			return compareIDs(a.Ids, b.Ids)
+		})
+	}
+	w.filterCache.setDeterministic(deterministic)
+}
+
+// IsDeterministic returns whether the world guarantees a well-defined iteration order.
+// See [World.SetDeterministic].
+func (w *World) IsDeterministic() bool {
+	return w.config.deterministic
+}
+
+// Removes the entity at the given index from an archetype,
+// and updates the indices of entities that were moved by the removal.
+func (w *World) removeRow(arch *archetype, index uint32) {
+	if !w.config.deterministic {
+		if arch.Remove(index) {
+			swapEntity := arch.GetEntity(index)
+			w.entities[swapEntity.id].index = index
+		}
+		return
+	}
+	arch.RemoveOrdered(index)
+	for i := index; i < arch.len; i++ {
+		w.entities[arch.GetEntity(i).id].index = i
+	}
+}
+
+// Inserts a node pointer at its position in deterministic order.
+func (w *World) insertNodePointer(node *archNode) {
+	idx, _ := slices.BinarySearchFunc(w.nodePointers, node, func(a, b *archNode) int {
+		return compareIDs(a.Ids, b.Ids)
+	})
+	w.nodePointers = slices.Insert(w.nodePointers, idx, node)
+}
+
+// Inserts an archetype into a slice sorted in deterministic order.
+func sortedInsert(arches []*archetype, arch *archetype) []*archetype {
+	idx, _ := slices.BinarySearchFunc(arches, arch, compareArchetypes)
+	return slices.Insert(arches, idx, arch)
+}
+
+// Removes an archetype from a slice, preserving the order of the remaining archetypes.
+func orderedRemove(arches []*archetype, arch *archetype) []*archetype {
+	idx := slices.Index(arches, arch)
+	if idx < 0 {
+		return arches
+	}
+	last := len(arches) - 1
+	copy(arches[idx:], arches[idx+1:])
+	arches[last] = nil
+	return arches[:last]
+}
+
+// Sorts archetypes in deterministic order.
+func sortArchetypes(arches []*archetype) {
+	slices.SortFunc(arches, compareArchetypes)
+}
+
+// Compares archetypes by their component IDs, then by their relation target.
+func compareArchetypes(a, b *archetype) int {
+	if c := compareIDs(a.node.Ids, b.node.Ids); c != 0 {
+		return c
+	}
+	return compareEntities(a.RelationTarget, b.RelationTarget)
+}
+
+// Compares sorted lists of component IDs lexicographically.
+func compareIDs(a, b []ID) int {
+	return slices.CompareFunc(a, b, func(x, y ID) int {
+		return cmp.Compare(x.id, y.id)
+	})
+}
+
+// Compares entities by ID, then by generation.
+func compareEntities(a, b Entity) int {
+	if c := cmp.Compare(a.id, b.id); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.gen, b.gen)
+}
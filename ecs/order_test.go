@@ -0,0 +1,166 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// queryEntities returns the entities matching a filter, in iteration order.
+func queryEntities(w *World, filter Filter) []Entity {
+	result := []Entity{}
+	query := w.Query(filter)
+	for query.Next() {
+		result = append(result, query.Entity())
+	}
+	return result
+}
+
+// queryComponents returns the sorted component IDs of the entities matching a filter, in iteration order.
+func queryComponents(w *World, filter Filter) [][]ID {
+	result := [][]ID{}
+	query := w.Query(filter)
+	for query.Next() {
+		result = append(result, w.Ids(query.Entity()))
+	}
+	return result
+}
+
+func TestWorldDeterministicArchetypes(t *testing.T) {
+	build := func(reverse bool) *World {
+		w := NewWorld()
+		posID := ComponentID[Position](&w)
+		velID := ComponentID[Velocity](&w)
+		rotID := ComponentID[rotation](&w)
+		w.SetDeterministic(true)
+
+		comps := [][]ID{{posID}, {posID, velID}, {posID, rotID}, {posID, velID, rotID}, {velID}}
+		if reverse {
+			for i, j := 0, len(comps)-1; i < j; i, j = i+1, j-1 {
+				comps[i], comps[j] = comps[j], comps[i]
+			}
+		}
+		for _, ids := range comps {
+			w.NewEntity(ids...)
+		}
+		assert.NoError(t, w.Validate())
+		return &w
+	}
+
+	w1 := build(false)
+	w2 := build(true)
+
+	filter := All()
+	assert.Equal(t, queryComponents(w1, filter), queryComponents(w2, filter))
+	assert.Equal(t, [][]ID{{id(0)}, {id(0), id(1)}, {id(0), id(1), id(2)}, {id(0), id(2)}, {id(1)}}, queryComponents(w1, filter))
+
+	cached1 := w1.Cache().Register(All(id(0)))
+	cached2 := w2.Cache().Register(All(id(0)))
+	assert.Equal(t, queryComponents(w1, &cached1), queryComponents(w2, &cached2))
+}
+
+func TestWorldDeterministicRemove(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	w.SetDeterministic(true)
+	assert.True(t, w.IsDeterministic())
+
+	entities := []Entity{}
+	for i := 0; i < 10; i++ {
+		entities = append(entities, NewBuilder(&w, posID).New())
+	}
+	for i, e := range entities {
+		pos := (*Position)(w.Get(e, posID))
+		pos.X = i
+	}
+
+	w.RemoveEntity(entities[2])
+	w.Add(entities[5], velID)
+	w.Remove(entities[5], velID)
+	assert.NoError(t, w.Validate())
+
+	expected := []Entity{entities[0], entities[1], entities[3], entities[4], entities[6], entities[7], entities[8], entities[9], entities[5]}
+	filter := All(posID)
+	assert.Equal(t, expected, queryEntities(&w, filter))
+
+	for _, e := range expected {
+		pos := (*Position)(w.Get(e, posID))
+		assert.Equal(t, int(e.id)-1, pos.X)
+	}
+}
+
+func TestWorldDeterministicRelations(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+	w.SetDeterministic(true)
+
+	targets := []Entity{}
+	for i := 0; i < 5; i++ {
+		targets = append(targets, w.NewEntity(posID))
+	}
+
+	children := []Entity{}
+	for i := len(targets) - 1; i >= 0; i-- {
+		children = append(children, NewBuilder(&w, relID).WithRelation(relID).New(targets[i]))
+	}
+	filter := All(relID)
+	cached := w.Cache().Register(filter)
+	assert.NoError(t, w.Validate())
+
+	expected := []Entity{children[4], children[3], children[2], children[1], children[0]}
+	assert.Equal(t, expected, queryEntities(&w, filter))
+	assert.Equal(t, expected, queryEntities(&w, &cached))
+
+	w.RemoveEntity(targets[1])
+	w.RemoveEntity(children[3])
+	assert.NoError(t, w.Validate())
+
+	expected = []Entity{children[4], children[2], children[1], children[0]}
+	assert.Equal(t, expected, queryEntities(&w, filter))
+	assert.Equal(t, expected, queryEntities(&w, &cached))
+
+	target := w.NewEntity(posID)
+	w.Relations().Set(children[4], relID, target)
+	assert.NoError(t, w.Validate())
+
+	expected = []Entity{children[2], children[1], children[0], children[4]}
+	assert.Equal(t, expected, queryEntities(&w, filter))
+	assert.Equal(t, expected, queryEntities(&w, &cached))
+}
+
+func TestWorldSetDeterministic(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	assert.False(t, w.IsDeterministic())
+
+	filter := All()
+	cached := w.Cache().Register(filter)
+
+	parent1 := w.NewEntity(velID)
+	parent2 := w.NewEntity(velID)
+	child2 := NewBuilder(&w, relID).WithRelation(relID).New(parent2)
+	child1 := NewBuilder(&w, relID).WithRelation(relID).New(parent1)
+	e := w.NewEntity(posID)
+
+	w.SetDeterministic(true)
+	assert.True(t, w.IsDeterministic())
+	assert.NoError(t, w.Validate())
+
+	expected := []Entity{e, parent1, parent2, child1, child2}
+	assert.Equal(t, expected, queryEntities(&w, filter))
+	assert.Equal(t, expected, queryEntities(&w, &cached))
+
+	w.SetDeterministic(false)
+	assert.False(t, w.IsDeterministic())
+	w.RemoveEntity(parent1)
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, []Entity{e, parent2, child1, child2}, queryEntities(&w, &cached))
+
+	query := w.Query(filter)
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.SetDeterministic(true) })
+	query.Close()
+}
@@ -0,0 +1,150 @@
+//go:build debug
+
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// archetypeStamps records the entities component pointers were handed out for, per archetype row.
+// Only used with build tag `debug`.
+type archetypeStamps struct {
+	entities []Entity         // Entity at each row when a pointer into the row was handed out. Zero for rows without pointers.
+	retired  []retiredStorage // Storage of the archetype before reallocations, if pointers into it were handed out.
+}
+
+// retiredStorage records the component columns of an archetype before its storage was reallocated.
+type retiredStorage struct {
+	columns  []retiredColumn // Address ranges of the component columns.
+	entities []Entity        // Stamped entities per row at the time of the reallocation.
+}
+
+// retiredColumn is the address range of a component column before reallocation.
+type retiredColumn struct {
+	start    uintptr // Address of the first element.
+	end      uintptr // Address after the last element.
+	itemSize uint32  // Component size.
+	comp     ID      // Component ID.
+}
+
+// stampPointer records a component pointer handed out by the world, and returns it.
+// Pointers are not recorded while read-only queries are open, as these may run concurrently.
+func (w *World) stampPointer(ptr unsafe.Pointer, arch *archetype, index uint32, comp ID) unsafe.Pointer {
+	if ptr == nil || arch.getLayout(comp).itemSize == 0 || w.locks.IsReadLocked() {
+		return ptr
+	}
+	s := &arch.stamps
+	if int(index) >= len(s.entities) {
+		s.entities = append(s.entities, make([]Entity, int(arch.cap)-len(s.entities))...)
+	}
+	s.entities[index] = arch.GetEntity(index)
+	return ptr
+}
+
+// retireStamps records the current component columns of an archetype before its storage is reallocated.
+// Only records columns if pointers into them were handed out.
+func (a *archetype) retireStamps() {
+	s := &a.stamps
+	if len(s.entities) == 0 {
+		return
+	}
+	columns := make([]retiredColumn, 0, len(a.node.Ids))
+	for _, id := range a.node.Ids {
+		lay := a.getLayout(id)
+		if lay.itemSize == 0 {
+			continue
+		}
+		start := uintptr(lay.pointer)
+		columns = append(columns, retiredColumn{
+			start:    start,
+			end:      start + uintptr(lay.itemSize)*uintptr(a.cap),
+			itemSize: lay.itemSize,
+			comp:     id,
+		})
+	}
+	s.retired = append(s.retired, retiredStorage{columns: columns, entities: s.entities})
+	s.entities = nil
+}
+
+// resetStamps removes all stamps of an archetype.
+func (a *archetype) resetStamps() {
+	a.stamps = archetypeStamps{}
+}
+
+// checkPointer panics if the given component pointer is stale.
+func (w *World) checkPointer(ptr any) {
+	value := reflect.ValueOf(ptr)
+	if kind := value.Kind(); kind != reflect.Pointer && kind != reflect.UnsafePointer {
+		panic(fmt.Sprintf("can't check pointer: expected a pointer, got %T", ptr))
+	}
+	addr := uintptr(value.UnsafePointer())
+
+	nodes := w.nodes.Len()
+	var i int32
+	for i = 0; i < nodes; i++ {
+		arches := w.nodes.Get(i).Archetypes()
+		archLen := arches.Len()
+		var j int32
+		for j = 0; j < archLen; j++ {
+			if w.checkArchetypePointer(arches.Get(j), addr) {
+				return
+			}
+		}
+	}
+	for i = 0; i < nodes; i++ {
+		arches := w.nodes.Get(i).Archetypes()
+		archLen := arches.Len()
+		var j int32
+		for j = 0; j < archLen; j++ {
+			w.checkRetiredPointer(arches.Get(j), addr)
+		}
+	}
+}
+
+// checkArchetypePointer checks a pointer against the current storage of an archetype.
+// Returns whether the pointer points into the archetype's storage.
+func (w *World) checkArchetypePointer(arch *archetype, addr uintptr) bool {
+	for _, id := range arch.node.Ids {
+		lay := arch.getLayout(id)
+		if lay.itemSize == 0 {
+			continue
+		}
+		start := uintptr(lay.pointer)
+		if addr < start || addr >= start+uintptr(lay.itemSize)*uintptr(arch.cap) {
+			continue
+		}
+		index := uint32((addr - start) / uintptr(lay.itemSize))
+		if int(index) >= len(arch.stamps.entities) {
+			return true
+		}
+		entity := arch.stamps.entities[index]
+		if entity.IsZero() {
+			return true
+		}
+		if index >= arch.len || arch.GetEntity(index) != entity {
+			panic(fmt.Sprintf("stale pointer to component %s of entity %v: the entity was moved or removed",
+				w.registry.Types[id.id].Name(), entity))
+		}
+		return true
+	}
+	return false
+}
+
+// checkRetiredPointer panics if a pointer points into the storage of an archetype before it was reallocated.
+func (w *World) checkRetiredPointer(arch *archetype, addr uintptr) {
+	for _, storage := range arch.stamps.retired {
+		for _, col := range storage.columns {
+			if addr < col.start || addr >= col.end {
+				continue
+			}
+			index := int((addr - col.start) / uintptr(col.itemSize))
+			if index >= len(storage.entities) || storage.entities[index].IsZero() {
+				continue
+			}
+			panic(fmt.Sprintf("stale pointer to component %s of entity %v: the archetype's storage was reallocated",
+				w.registry.Types[col.comp.id].Name(), storage.entities[index]))
+		}
+	}
+}
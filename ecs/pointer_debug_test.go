@@ -0,0 +1,76 @@
+//go:build debug
+
+package ecs
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPointer(t *testing.T) {
+	w := NewWorld(8)
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	e0 := w.NewEntity(posID)
+	e1 := w.NewEntity(posID)
+
+	pos := (*Position)(w.Get(e1, posID))
+	w.CheckPointer(pos)
+	w.CheckPointer(unsafe.Pointer(pos))
+	w.CheckPointer(&Position{})
+
+	w.RemoveEntity(e0)
+	assert.PanicsWithValue(t,
+		"stale pointer to component Position of entity {2 0}: the entity was moved or removed",
+		func() { w.CheckPointer(pos) })
+
+	pos = (*Position)(w.Get(e1, posID))
+	w.CheckPointer(pos)
+	w.Add(e1, velID)
+	assert.Panics(t, func() { w.CheckPointer(pos) })
+
+	pos = (*Position)(w.GetUnchecked(e1, posID))
+	w.Batch().New(100, posID, velID)
+	assert.PanicsWithValue(t,
+		"stale pointer to component Position of entity {2 0}: the archetype's storage was reallocated",
+		func() { w.CheckPointer(pos) })
+
+	query := w.Query(All(posID, velID))
+	query.Next()
+	pos = (*Position)(query.Get(posID))
+	query.Close()
+	w.CheckPointer(pos)
+	w.RemoveEntity(query.Entity())
+	assert.Panics(t, func() { w.CheckPointer(pos) })
+
+	assert.PanicsWithValue(t, "can't check pointer: expected a pointer, got ecs.Position",
+		func() { w.CheckPointer(Position{}) })
+}
+
+func TestCheckPointerMemory(t *testing.T) {
+	w := NewWorld(8)
+	posID := ComponentID[Position](&w)
+
+	e := w.NewEntity(posID)
+	arch := w.entities[e.id].arch
+	for i := 0; i < 100; i++ {
+		w.Get(e, posID)
+	}
+	assert.Equal(t, 8, len(arch.stamps.entities))
+	assert.Empty(t, arch.stamps.retired)
+
+	w.Batch().New(100, posID)
+	assert.Empty(t, arch.stamps.entities)
+	assert.Equal(t, 1, len(arch.stamps.retired))
+
+	w.Batch().New(100, posID)
+	assert.Equal(t, 1, len(arch.stamps.retired))
+
+	w.Get(e, posID)
+	w.Reset()
+	assert.Empty(t, arch.stamps.entities)
+	assert.Empty(t, arch.stamps.retired)
+}
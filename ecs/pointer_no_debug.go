@@ -0,0 +1,18 @@
+//go:build !debug
+
+package ecs
+
+import "unsafe"
+
+// archetypeStamps records the entities component pointers were handed out for. Empty without build tag `debug`.
+type archetypeStamps struct{}
+
+func (w *World) stampPointer(ptr unsafe.Pointer, arch *archetype, index uint32, comp ID) unsafe.Pointer {
+	return ptr
+}
+
+func (a *archetype) retireStamps() {}
+
+func (a *archetype) resetStamps() {}
+
+func (w *World) checkPointer(ptr any) {}
@@ -3,6 +3,7 @@ package ecs
 import (
 	"fmt"
 	"math"
+	"sync"
 )
 
 type number interface {
@@ -15,8 +16,15 @@ type entityPool struct {
 	entities  []Entity
 	next      eid
 	available uint32
+	reserved  []eid       // Reserved entity IDs that are not flushed yet.
+	pending   uint32      // Number of reserved IDs beyond the end of entities, not flushed yet.
+	open      uint32      // Number of reserved entities that are not materialized yet.
+	lock      *sync.Mutex // Lock for concurrent reservation.
 }
 
+// reservedID marks entries of reserved entities that are not materialized yet.
+const reservedID = eid(math.MaxUint32)
+
 // newEntityPool creates a new, initialized Entity pool.
 func newEntityPool(initialCapacity uint32) entityPool {
 	entities := make([]Entity, 1, initialCapacity)
@@ -25,6 +33,7 @@ func newEntityPool(initialCapacity uint32) entityPool {
 		entities:  entities,
 		next:      0,
 		available: 0,
+		lock:      &sync.Mutex{},
 	}
 }
 
@@ -46,6 +55,56 @@ func (p *entityPool) getNew() Entity {
 	return e
 }
 
+// Reserve returns a fresh or recycled entity without changing the entities slice,
+// so that it is safe to call concurrently with reading from the pool.
+// The entity needs to be flushed by [entityPool.Flush] before it can be materialized.
+func (p *entityPool) Reserve() Entity {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var e Entity
+	if p.available == 0 {
+		e = newEntity(eid(len(p.entities)) + eid(p.pending))
+		p.pending++
+	} else {
+		e = Entity{p.next, p.entities[p.next].gen}
+		p.next = p.entities[p.next].id
+		p.available--
+	}
+	p.reserved = append(p.reserved, e.id)
+	p.open++
+	return e
+}
+
+// Flush marks the entries of reserved entities, and allocates entries for new reserved entities.
+// Returns whether the entities slice was extended.
+func (p *entityPool) Flush() bool {
+	if len(p.reserved) == 0 {
+		return false
+	}
+	for i := uint32(0); i < p.pending; i++ {
+		p.entities = append(p.entities, Entity{reservedID, 0})
+	}
+	for _, id := range p.reserved {
+		p.entities[id].id = reservedID
+	}
+	extended := p.pending > 0
+	p.reserved = p.reserved[:0]
+	p.pending = 0
+	return extended
+}
+
+// IsReserved returns whether an entity is reserved and flushed, but not materialized.
+func (p *entityPool) IsReserved(e Entity) bool {
+	return e.id != 0 && int(e.id) < len(p.entities) && p.entities[e.id] == Entity{reservedID, e.gen}
+}
+
+// Materialize makes a reserved and flushed entity alive.
+func (p *entityPool) Materialize(e Entity) {
+	p.entities[e.id].id = e.id
+	p.open--
+}
+
 // Recycle hands an entity back for recycling.
 func (p *entityPool) Recycle(e Entity) {
 	if e.id == 0 {
@@ -61,16 +120,20 @@ func (p *entityPool) Reset() {
 	p.entities = p.entities[:1]
 	p.next = 0
 	p.available = 0
+	p.reserved = p.reserved[:0]
+	p.pending = 0
+	p.open = 0
 }
 
 // Alive returns whether an entity is still alive, based on the entity's generations.
+// Recycled and reserved entities are not alive, as their entries don't hold their own ID.
 func (p *entityPool) Alive(e Entity) bool {
-	return e.gen == p.entities[e.id].gen
+	return int(e.id) < len(p.entities) && p.entities[e.id] == e
 }
 
-// Len returns the current number of used entities.
+// Len returns the current number of used entities. Reserved entities are not counted.
 func (p *entityPool) Len() int {
-	return len(p.entities) - 1 - int(p.available)
+	return len(p.entities) - 1 - int(p.available) - int(p.open-p.pending)
 }
 
 // Cap returns the current capacity (used and recycled entities).
@@ -0,0 +1,25 @@
+//go:build !profile
+
+package ecs
+
+const isProfile = false
+
+// queryProfile holds per-query profiling data. Empty without build tag `profile`.
+type queryProfile struct{}
+
+// queryProfiler records profiling data of queries. Empty without build tag `profile`.
+type queryProfiler struct{}
+
+func (w *World) profileQuery(q *Query, filter Filter) {}
+
+func (w *World) profileClose(q *Query) {}
+
+func (w *World) profileStats() {}
+
+func (w *World) profileReset() {}
+
+func (w *World) profileUnregister(id uint32) {}
+
+func (q *Query) profileArchetype() {}
+
+func (q *Query) profileEntity() {}
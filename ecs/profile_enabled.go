@@ -0,0 +1,244 @@
+//go:build profile
+
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mlange-42/arche/ecs/stats"
+)
+
+const isProfile = true
+
+// queryProfile holds per-query profiling data.
+type queryProfile struct {
+	entry *profileEntry // Profiling entry of the query's filter. Nil for unprofiled queries.
+	start time.Time     // Creation time of the query.
+}
+
+// profileQuery starts profiling a query.
+func (w *World) profileQuery(q *Query, filter Filter) {
+	if w.profiler == nil {
+		w.profiler = newQueryProfiler()
+	}
+	entry := w.profiler.Entry(filter)
+	entry.queries++
+	q.profile = queryProfile{entry: entry, start: time.Now()}
+}
+
+// profileClose finishes profiling a query.
+func (w *World) profileClose(q *Query) {
+	if q.profile.entry == nil {
+		return
+	}
+	q.profile.entry.time += time.Since(q.profile.start)
+	q.profile.entry = nil
+}
+
+// profileArchetype records a visited archetype and its first entity.
+func (q *Query) profileArchetype() {
+	if q.profile.entry == nil {
+		return
+	}
+	q.profile.entry.archetypes++
+	q.profile.entry.entities++
+}
+
+// profileEntity records an iterated entity.
+func (q *Query) profileEntity() {
+	if q.profile.entry == nil {
+		return
+	}
+	q.profile.entry.entities++
+}
+
+// profileUnregister removes the profiling data of an unregistered cached filter,
+// as its ID may be re-used by the next registered filter.
+func (w *World) profileUnregister(id uint32) {
+	if w.profiler == nil {
+		return
+	}
+	delete(w.profiler.byCached, id)
+}
+
+// profileKey identifies uncached filters with the same description.
+// Allows to build human-readable labels lazily.
+type profileKey struct {
+	include   Mask         // Included components, for masks and mask filters.
+	exclude   Mask         // Excluded components, for mask filters.
+	typ       reflect.Type // Filter type, for other filters.
+	relations uint8        // Nesting depth of relation filters.
+	kind      uint8        // Kind of the innermost filter.
+}
+
+// Kinds of filters in a [profileKey].
+const (
+	keyMask uint8 = iota
+	keyMaskFilter
+	keyOther
+)
+
+// newProfileKey creates the key for a filter.
+func newProfileKey(filter Filter) profileKey {
+	key := profileKey{}
+	for {
+		rf, ok := filter.(*RelationFilter)
+		if !ok {
+			break
+		}
+		key.relations++
+		filter = rf.Filter
+	}
+	switch f := filter.(type) {
+	case *Mask:
+		key.include = *f
+	case Mask:
+		key.include = f
+	case *MaskFilter:
+		key.kind = keyMaskFilter
+		key.include = f.Include
+		key.exclude = f.Exclude
+	default:
+		key.kind = keyOther
+		key.typ = reflect.TypeOf(filter)
+	}
+	return key
+}
+
+// label returns a human-readable description of the key's filter.
+func (k *profileKey) label(reg *componentRegistry) string {
+	var label string
+	switch k.kind {
+	case keyMask:
+		label = maskLabel("All", &k.include, reg)
+	case keyMaskFilter:
+		label = maskLabel("All", &k.include, reg) + " " + maskLabel("Without", &k.exclude, reg)
+	default:
+		label = fmt.Sprintf("%v", k.typ)
+	}
+	for i := uint8(0); i < k.relations; i++ {
+		label = "Relation(" + label + ")"
+	}
+	return label
+}
+
+// profileEntry holds profiling data for a single filter.
+type profileEntry struct {
+	key        profileKey    // Key of uncached filters.
+	cached     Filter        // Underlying filter of cached filters. Nil for uncached filters.
+	cacheID    uint32        // ID of cached filters.
+	queries    int           // Number of queries created.
+	archetypes int           // Number of archetypes visited.
+	entities   int           // Number of entities iterated.
+	time       time.Duration // Wall time between query creation and closing.
+}
+
+// queryProfiler records profiling data of queries, per filter.
+// Only used with build tag `profile`.
+type queryProfiler struct {
+	byKey    map[profileKey]*profileEntry // Entries for uncached filters, by key.
+	byCached map[uint32]*profileEntry     // Entries for cached filters, by cache ID.
+}
+
+// newQueryProfiler creates a new queryProfiler.
+func newQueryProfiler() *queryProfiler {
+	return &queryProfiler{
+		byKey:    map[profileKey]*profileEntry{},
+		byCached: map[uint32]*profileEntry{},
+	}
+}
+
+// Entry returns the entry for a filter, and creates it if necessary.
+func (p *queryProfiler) Entry(filter Filter) *profileEntry {
+	if cached, ok := filter.(*CachedFilter); ok {
+		if e, ok := p.byCached[cached.id]; ok {
+			return e
+		}
+		e := &profileEntry{cached: cached.filter, cacheID: cached.id}
+		p.byCached[cached.id] = e
+		return e
+	}
+	key := newProfileKey(filter)
+	if e, ok := p.byKey[key]; ok {
+		return e
+	}
+	e := &profileEntry{key: key}
+	p.byKey[key] = e
+	return e
+}
+
+// Stats returns the profiling data as statistics, sorted by label.
+func (p *queryProfiler) Stats(queries []stats.Query, reg *componentRegistry) []stats.Query {
+	queries = queries[:0]
+	for _, e := range p.byCached {
+		queries = append(queries, e.stats(reg))
+	}
+	for _, e := range p.byKey {
+		queries = append(queries, e.stats(reg))
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Filter < queries[j].Filter })
+	return queries
+}
+
+// Reset removes all profiling data.
+func (p *queryProfiler) Reset() {
+	clear(p.byKey)
+	clear(p.byCached)
+}
+
+// label returns a human-readable description of the entry's filter.
+func (e *profileEntry) label(reg *componentRegistry) string {
+	if e.cached != nil {
+		return fmt.Sprintf("Cached(%d): %s", e.cacheID, filterLabel(e.cached, reg))
+	}
+	return e.key.label(reg)
+}
+
+// stats converts the entry to statistics.
+func (e *profileEntry) stats(reg *componentRegistry) stats.Query {
+	return stats.Query{
+		Filter:     e.label(reg),
+		Queries:    e.queries,
+		Archetypes: e.archetypes,
+		Entities:   e.entities,
+		Time:       e.time,
+	}
+}
+
+// filterLabel returns a human-readable description of a filter.
+func filterLabel(filter Filter, reg *componentRegistry) string {
+	key := newProfileKey(filter)
+	return key.label(reg)
+}
+
+// maskLabel returns a human-readable description of a mask.
+// Only registered components are listed.
+func maskLabel(name string, mask *Mask, reg *componentRegistry) string {
+	names := []string{}
+	for _, id := range reg.IDs {
+		if mask.Get(ID{id: id}) {
+			names = append(names, reg.Types[id].Name())
+		}
+	}
+	return name + "(" + strings.Join(names, ", ") + ")"
+}
+
+// profileStats updates the query statistics.
+func (w *World) profileStats() {
+	if w.profiler == nil {
+		w.stats.Queries = w.stats.Queries[:0]
+		return
+	}
+	w.stats.Queries = w.profiler.Stats(w.stats.Queries, &w.registry)
+}
+
+// profileReset resets the query statistics.
+func (w *World) profileReset() {
+	if w.profiler != nil {
+		w.profiler.Reset()
+	}
+}
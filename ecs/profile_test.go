@@ -0,0 +1,116 @@
+//go:build profile
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryProfiler(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	w.Batch().New(10, posID)
+	w.Batch().New(5, posID, velID)
+
+	assert.Empty(t, w.Stats().Queries)
+
+	for i := 0; i < 2; i++ {
+		query := w.Query(All(posID))
+		for query.Next() {
+		}
+	}
+
+	filter := All(posID).Without(velID)
+	query := w.Query(&filter)
+	query.Next()
+	query.Close()
+
+	cf := w.Cache().Register(All(velID))
+	query = w.Query(&cf)
+	for query.Next() {
+	}
+
+	stats := w.Stats()
+	assert.Equal(t, 3, len(stats.Queries))
+
+	q := stats.Queries[0]
+	assert.Equal(t, "All(Position)", q.Filter)
+	assert.Equal(t, 2, q.Queries)
+	assert.Equal(t, 4, q.Archetypes)
+	assert.Equal(t, 30, q.Entities)
+
+	q = stats.Queries[1]
+	assert.Equal(t, "All(Position) Without(Velocity)", q.Filter)
+	assert.Equal(t, 1, q.Queries)
+	assert.Equal(t, 1, q.Archetypes)
+	assert.Equal(t, 1, q.Entities)
+
+	q = stats.Queries[2]
+	assert.Equal(t, "Cached(0): All(Velocity)", q.Filter)
+	assert.Equal(t, 1, q.Queries)
+	assert.Equal(t, 1, q.Archetypes)
+	assert.Equal(t, 5, q.Entities)
+
+	w.ResetQueryStats()
+	assert.Empty(t, w.Stats().Queries)
+}
+
+func TestQueryProfilerUnregister(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	w.Batch().New(10, posID)
+
+	cf := w.Cache().Register(All(posID))
+	query := w.Query(&cf)
+	for query.Next() {
+	}
+	assert.Equal(t, "Cached(0): All(Position)", w.Stats().Queries[0].Filter)
+	w.Cache().Unregister(&cf)
+	assert.Empty(t, w.Stats().Queries)
+
+	cf = w.Cache().Register(All(velID))
+	query = w.Query(&cf)
+	for query.Next() {
+	}
+
+	stats := w.Stats()
+	assert.Equal(t, 1, len(stats.Queries))
+	q := stats.Queries[0]
+	assert.Equal(t, "Cached(1): All(Velocity)", q.Filter)
+	assert.Equal(t, 1, q.Queries)
+	assert.Equal(t, 0, q.Entities)
+}
+
+func TestQueryProfilerAllocs(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	w.Batch().New(10, posID)
+	mask := All(posID)
+	filter := All(posID).Without(velID)
+	relFilter := RelationFilter{Filter: All(relID)}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		query := w.Query(&mask)
+		query.Close()
+		query = w.Query(&filter)
+		query.Close()
+		query = w.Query(&relFilter)
+		query.Close()
+	})
+	assert.Equal(t, 0.0, allocs)
+
+	labels := []string{}
+	for _, q := range w.Stats().Queries {
+		labels = append(labels, q.Filter)
+	}
+	assert.Equal(t, []string{"All(Position)", "All(Position) Without(Velocity)", "Relation(All(testRelationA))"}, labels)
+}
@@ -7,7 +7,8 @@ import (
 
 // Query is an iterator to iterate entities, filtered by a [Filter].
 //
-// Create queries through the [World] using [World.Query]. See there for more details.
+// Create queries through the [World] using [World.Query], or [World.QueryRead] for read-only queries.
+// See there for more details.
 //
 // In case you get error messages like "index out of range [-1]" or "invalid memory address or nil pointer dereference",
 // try running with build tag `debug`.
@@ -16,6 +17,7 @@ import (
 // [github.com/mlange-42/arche/generic.Query2], etc.
 // For advanced filtering, see package [github.com/mlange-42/arche/filter].
 type Query struct {
+	profile        queryProfile     // Profiling data. Only used with build tag `profile`.
 	nodeArchetypes archetypes       // The query's archetypes of the current node.
 	filter         Filter           // The filter used by the query.
 	access         *archetypeAccess // Access helper for the archetype currently being iterated.
@@ -23,14 +25,18 @@ type Query struct {
 	world          *World           // The [World].
 	nodes          []*archNode      // The query's nodes.
 	archetypes     []*archetype     // The query's filtered archetypes.
+	predicate      *PredicateFilter // Predicate filter, if any.
+	predicatePtrs  []unsafe.Pointer // Component pointers passed to the predicate.
 	entityIndex    uint32           // Iteration index of the current [Entity] current archetype.
 	entityIndexMax uint32           // Maximum entity index in the current archetype.
+	matchIndexMax  uint32           // Maximum entity index in the current archetype, for queries with a predicate.
 	archIndex      int32            // Iteration index of the current archetype.
 	nodeIndex      int32            // Iteration index of the current archetype.
 	count          int32            // Cached entity count.
 	lockBit        uint8            // The bit that was used to lock the [World] when the query was created.
 	isFiltered     bool             // Whether the list of archetype nodes is already filtered.
 	isBatch        bool             // Marks the query as a query over a batch iteration.
+	readOnly       bool             // Marks the query as read-only, see [World.QueryRead].
 }
 
 // newQuery creates a new Filter
@@ -84,6 +90,7 @@ func (q *Query) Next() bool {
 	q.checkNext()
 	if q.entityIndex < q.entityIndexMax {
 		q.entityIndex++
+		q.profileEntity()
 		return true
 	}
 	// outline to allow inlining of the fast path
@@ -99,9 +106,10 @@ func (q *Query) Has(comp ID) bool {
 // Get returns a pointer to the given component at the iterator's position.
 //
 // ⚠️ Important: The obtained pointer should not be stored persistently!
+// With build tag `debug`, stale pointers can be detected with [World.CheckPointer].
 func (q *Query) Get(comp ID) unsafe.Pointer {
 	q.checkGet()
-	return q.access.Get(q.entityIndex, comp)
+	return q.world.stampPointer(q.access.Get(q.entityIndex, comp), q.archetype, q.entityIndex, comp)
 }
 
 // Entity returns the entity at the iterator's position.
@@ -129,6 +137,8 @@ func (q *Query) Entity() Entity {
 // The total number of archetypes in the world has no performance impact for registered filters,
 // while for filters that are not registered, it has.
 //
+// For queries with a [PredicateFilter], the predicate is evaluated for all entities up to the index.
+//
 // Panics if the index is out of range, as indicated by [Query.Count].
 func (q *Query) EntityAt(index int) Entity {
 	return q.entityAt(index)
@@ -163,10 +173,20 @@ func (q *Query) relationUnchecked(comp ID) Entity {
 // The query is closed in this case.
 //
 // Query.Step(1) is equivalent to [Query.Next](), although probably slower.
+//
+// For queries with a [PredicateFilter], only entities that match the predicate are counted as steps.
 func (q *Query) Step(step int) bool {
 	if step <= 0 {
 		panic("step size must be positive")
 	}
+	if q.predicate != nil {
+		for ; step > 0; step-- {
+			if !q.nextMatching() {
+				return false
+			}
+		}
+		return true
+	}
 	var ok bool
 	for {
 		step, ok = q.stepArchetype(uint32(step))
@@ -187,6 +207,9 @@ func (q *Query) Step(step int) bool {
 // Involves a small overhead of iterating through archetypes when called the first time.
 // However, this is still much faster than manual counting via iteration.
 //
+// For queries with a [PredicateFilter], the predicate is evaluated for all entities,
+// which is significantly slower than counting for other filters.
+//
 // Does not close the query.
 func (q *Query) Count() int {
 	if q.count >= 0 {
@@ -219,7 +242,18 @@ func (q *Query) Close() {
 }
 
 // nextArchetype proceeds to the next archetype, and returns whether this was successful/possible.
+//
+// For queries with a predicate, proceeds to the next matching entity instead.
+// These queries keep entityIndexMax at zero, so that the fast path in [Query.Next] never applies.
 func (q *Query) nextArchetype() bool {
+	if q.predicate != nil {
+		return q.nextMatching()
+	}
+	return q.nextArchetypeAny()
+}
+
+// nextArchetypeAny proceeds to the next archetype of any kind of query.
+func (q *Query) nextArchetypeAny() bool {
 	if q.isFiltered {
 		return q.nextArchetypeFiltered()
 	}
@@ -249,6 +283,7 @@ func (q *Query) nextArchetypeBatch() bool {
 			batch := q.nodeArchetypes.(*batchArchetypes)
 			q.entityIndex = batch.StartIndex[q.archIndex]
 			q.entityIndexMax = batch.EndIndex[q.archIndex] - 1
+			q.profileArchetype()
 			return true
 		}
 	}
@@ -268,6 +303,8 @@ func (q *Query) nextArchetypeSimple() bool {
 		q.archetype = a
 		q.entityIndex = 0
 		q.entityIndexMax = aLen - 1
+		q.captureArchetype()
+		q.profileArchetype()
 		return true
 	}
 	return false
@@ -286,6 +323,8 @@ func (q *Query) nextArchetypeFiltered() bool {
 		q.archetype = a
 		q.entityIndex = 0
 		q.entityIndexMax = aLen - 1
+		q.captureArchetype()
+		q.profileArchetype()
 		return true
 	}
 	q.world.closeQuery(q)
@@ -321,6 +360,8 @@ func (q *Query) nextNode() bool {
 			archLen := arch.Len()
 			if archLen > 0 {
 				q.setArchetype(nil, &arch.archetypeAccess, arch, arch.index, archLen-1)
+				q.captureArchetype()
+				q.profileArchetype()
 				return true
 			}
 			continue
@@ -330,6 +371,8 @@ func (q *Query) nextNode() bool {
 			target := rf.Target
 			if arch, ok := n.archetypeMap[target]; ok && arch.Len() > 0 {
 				q.setArchetype(nil, &arch.archetypeAccess, arch, arch.index, arch.Len()-1)
+				q.captureArchetype()
+				q.profileArchetype()
 				return true
 			}
 			continue
@@ -345,6 +388,128 @@ func (q *Query) nextNode() bool {
 	return false
 }
 
+// captureArchetype saves the entities of the current archetype in the world's active transaction, if any.
+// Read-only queries don't capture, as they must not modify components.
+func (q *Query) captureArchetype() {
+	if q.world.tx != nil && !q.readOnly {
+		q.world.tx.captureArchetype(q.archetype, q.entityIndexMax+1)
+	}
+}
+
+// nextMatching proceeds to the next [Entity] that matches the query's predicate.
+func (q *Query) nextMatching() bool {
+	for {
+		if q.entityIndex < q.matchIndexMax {
+			q.entityIndex++
+			q.profileEntity()
+		} else if q.nextArchetypeAny() {
+			q.matchIndexMax = q.entityIndexMax
+			q.entityIndexMax = 0
+		} else {
+			return false
+		}
+		if q.matches(q.access, q.entityIndex) {
+			return true
+		}
+	}
+}
+
+// matches evaluates the query's predicate for the entity at the given index.
+func (q *Query) matches(access *archetypeAccess, index uint32) bool {
+	for i, id := range q.predicate.Components {
+		q.predicatePtrs[i] = access.Get(index, id)
+	}
+	return q.predicate.Predicate(q.predicatePtrs)
+}
+
+// forPredicateArchetypes calls fn for each archetype to check for a query with a predicate, until fn returns false.
+// Walks the query's nodes and their archetypes in place, like [Query.nextNode].
+func (q *Query) forPredicateArchetypes(fn func(arch *archetype) bool) {
+	if q.isFiltered {
+		for _, arch := range q.archetypes {
+			if !fn(arch) {
+				return
+			}
+		}
+		return
+	}
+
+	for _, nd := range q.nodes {
+		if !nd.IsActive || !nd.Matches(q.filter) {
+			continue
+		}
+
+		arches := nd.Archetypes()
+
+		if !nd.HasRelation {
+			// There should be at least one archetype.
+			// Otherwise, the node would be inactive.
+			if !fn(arches.Get(0)) {
+				return
+			}
+			continue
+		}
+
+		if rf, ok := q.filter.(*RelationFilter); ok {
+			if arch, ok := nd.archetypeMap[rf.Target]; ok && !fn(arch) {
+				return
+			}
+			continue
+		}
+
+		nArch := arches.Len()
+		var j int32
+		for j = 0; j < nArch; j++ {
+			if !fn(arches.Get(j)) {
+				return
+			}
+		}
+	}
+}
+
+func (q *Query) countMatching() int {
+	count := 0
+	q.forPredicateArchetypes(func(arch *archetype) bool {
+		ln := arch.Len()
+		var j uint32
+		for j = 0; j < ln; j++ {
+			if q.matches(&arch.archetypeAccess, j) {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+func (q *Query) entityAtMatching(index int) Entity {
+	if index < 0 {
+		panic("can't get entity at negative index")
+	}
+	count := 0
+	var entity Entity
+	found := false
+	q.forPredicateArchetypes(func(arch *archetype) bool {
+		ln := arch.Len()
+		var j uint32
+		for j = 0; j < ln; j++ {
+			if !q.matches(&arch.archetypeAccess, j) {
+				continue
+			}
+			if count == index {
+				entity, found = arch.GetEntity(j), true
+				return false
+			}
+			count++
+		}
+		return true
+	})
+	if !found {
+		panic(fmt.Sprintf("query index out of range: index %d, length %d", index, count))
+	}
+	return entity
+}
+
 func (q *Query) setArchetype(arches archetypes, access *archetypeAccess, arch *archetype, archIndex int32, maxIndex uint32) {
 	q.nodeArchetypes = arches
 	q.archIndex = archIndex
@@ -363,6 +528,9 @@ func (q *Query) stepArchetype(step uint32) (int, bool) {
 }
 
 func (q *Query) countEntities() int {
+	if q.predicate != nil {
+		return q.countMatching()
+	}
 	var count uint32 = 0
 
 	if q.isFiltered {
@@ -417,6 +585,9 @@ func (q *Query) countEntities() int {
 }
 
 func (q *Query) entityAt(index int) Entity {
+	if q.predicate != nil {
+		return q.entityAtMatching(index)
+	}
 	if index < 0 {
 		panic("can't get entity at negative index")
 	}
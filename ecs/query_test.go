@@ -2,7 +2,9 @@ package ecs
 
 import (
 	"math/rand"
+	"sync"
 	"testing"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -749,3 +751,76 @@ func BenchmarkQueryEntityAt_10Arch_1000_Registered(b *testing.B) {
 	}
 	_ = e
 }
+
+func TestQueryRead(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	rotID := ComponentID[rotation](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent := w.NewEntity()
+	for i := 0; i < 10; i++ {
+		e := w.NewEntity(posID)
+		(*Position)(w.Get(e, posID)).X = i
+		NewBuilder(&w, posID, rotID, relID).WithRelation(relID).New(parent)
+	}
+
+	queries := []Query{}
+	for i := 0; i < 2*MaskTotalBits; i++ {
+		queries = append(queries, w.QueryRead(All(posID)))
+	}
+	assert.True(t, w.IsLocked())
+	assert.True(t, w.locks.locks.IsZero())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.NewEntity() })
+	assert.NoError(t, w.Validate())
+
+	for i := range queries {
+		queries[i].Close()
+	}
+	assert.False(t, w.IsLocked())
+	assert.PanicsWithValue(t, "unbalanced unlock. Did you close a query that was already iterated?", func() { queries[0].Close() })
+	assert.False(t, w.IsLocked())
+
+	query := w.QueryRead(All(posID))
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	assert.Equal(t, 20, cnt)
+	assert.False(t, w.IsLocked())
+	assert.PanicsWithValue(t, "unbalanced unlock. Did you close a query that was already iterated?", func() { query.Close() })
+
+	cached := w.Cache().Register(All(posID, rotID))
+	relFilter := NewRelationFilter(All(relID), parent)
+	predicate := NewPredicateFilter(All(posID), func(p []unsafe.Pointer) bool {
+		return (*Position)(p[0]).X >= 5
+	}, posID)
+	filters := []Filter{All(posID), &cached, &relFilter, &predicate}
+	expected := []int{20, 10, 10, 5}
+
+	regular := w.Query(All(rotID))
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j, filter := range filters {
+				query := w.QueryRead(filter)
+				assert.Equal(t, expected[j], query.Count())
+				assert.False(t, query.EntityAt(expected[j]-1).IsZero())
+				cnt := 0
+				for query.Next() {
+					_ = query.Get(posID)
+					cnt++
+				}
+				assert.Equal(t, expected[j], cnt)
+			}
+		}()
+	}
+	for regular.Next() {
+	}
+	wg.Wait()
+
+	assert.False(t, w.IsLocked())
+	assert.NoError(t, w.Validate())
+}
@@ -0,0 +1,218 @@
+package ecs_test
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Component kinds of the reference model.
+const (
+	refA = iota // Component with a value.
+	refB        // Component with a value.
+	refC        // Zero-sized component.
+	refR        // Relation component with a value.
+	refKinds
+)
+
+// refAll is the bit mask of all component kinds of the reference model.
+const refAll uint8 = 1<<refKinds - 1
+
+// Component types for the reference model.
+type (
+	refCompA struct{ V int32 }
+	refCompB struct{ V int32 }
+	refCompC struct{}
+	refCompR struct {
+		ecs.Relation
+		V int32
+	}
+)
+
+// refEntity is the state of an entity in the reference model.
+type refEntity struct {
+	values [refKinds]int32 // Component values, by kind. Unused for refC.
+	comps  uint8           // Bit mask of component kinds.
+	target ecs.Entity      // Relation target, if the entity has refR.
+}
+
+// refEvent is a normalized entity event, for comparing the events of the reference model and a real world.
+type refEvent struct {
+	Entity    ecs.Entity
+	Created   bool
+	Removed   bool
+	Added     uint8
+	Dropped   uint8
+	OldTarget ecs.Entity
+	NewTarget ecs.Entity
+}
+
+// refFilter is a filter in terms of component kinds of the reference model.
+type refFilter struct {
+	include, exclude uint8
+	hasTarget        bool
+	target           ecs.Entity
+}
+
+// Matches returns whether an entity matches the filter.
+func (f *refFilter) Matches(e *refEntity) bool {
+	if e.comps&f.include != f.include || e.comps&f.exclude != 0 {
+		return false
+	}
+	if f.hasTarget {
+		return e.comps&(1<<refR) != 0 && e.target == f.target
+	}
+	return true
+}
+
+// refWorld is a naive, map-based reference implementation of the semantics of [ecs.World].
+//
+// It does not assign entity IDs itself, but takes them from the world under test,
+// and only checks that they are valid.
+type refWorld struct {
+	entities map[ecs.Entity]*refEntity // Alive entities.
+	order    []ecs.Entity              // Alive entities, in a deterministic order.
+	used     map[ecs.Entity]bool       // Entities that were alive at some point since the last reset.
+	events   []refEvent                // Expected events of the current operation.
+}
+
+// newRefWorld creates an empty reference model.
+func newRefWorld() *refWorld {
+	return &refWorld{
+		entities: map[ecs.Entity]*refEntity{},
+		used:     map[ecs.Entity]bool{},
+	}
+}
+
+// Clone returns a deep copy of the reference model, without pending events.
+func (w *refWorld) Clone() *refWorld {
+	c := &refWorld{
+		entities: make(map[ecs.Entity]*refEntity, len(w.entities)),
+		order:    append([]ecs.Entity{}, w.order...),
+		used:     make(map[ecs.Entity]bool, len(w.used)),
+	}
+	for e, ent := range w.entities {
+		cp := *ent
+		c.entities[e] = &cp
+	}
+	for e := range w.used {
+		c.used[e] = true
+	}
+	return c
+}
+
+// Len returns the number of alive entities.
+func (w *refWorld) Len() int {
+	return len(w.order)
+}
+
+// Pick returns the alive entity at the given position, modulo the number of entities.
+func (w *refWorld) Pick(index int) ecs.Entity {
+	return w.order[index%len(w.order)]
+}
+
+// Get returns the state of an alive entity.
+func (w *refWorld) Get(e ecs.Entity) *refEntity {
+	return w.entities[e]
+}
+
+// NewEntity adds an entity that was created by the world under test.
+func (w *refWorld) NewEntity(e ecs.Entity, comps uint8, target ecs.Entity) error {
+	if e.IsZero() {
+		return fmt.Errorf("created entity is the zero entity")
+	}
+	if w.used[e] {
+		return fmt.Errorf("created entity %v was used before", e)
+	}
+	for _, other := range w.order {
+		if other.ID() == e.ID() {
+			return fmt.Errorf("created entity %v has the same ID as alive entity %v", e, other)
+		}
+	}
+	if comps&(1<<refR) == 0 {
+		target = ecs.Entity{}
+	}
+	w.entities[e] = &refEntity{comps: comps, target: target}
+	w.order = append(w.order, e)
+	w.used[e] = true
+	w.events = append(w.events, refEvent{Entity: e, Created: true, Added: comps, NewTarget: target})
+	return nil
+}
+
+// RemoveEntity removes an alive entity.
+func (w *refWorld) RemoveEntity(e ecs.Entity) {
+	ent := w.entities[e]
+	w.events = append(w.events, refEvent{Entity: e, Removed: true, Dropped: ent.comps, OldTarget: ent.target})
+	delete(w.entities, e)
+	for i, other := range w.order {
+		if other == e {
+			w.order = append(w.order[:i], w.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Exchange adds and removes components of an alive entity.
+// Added components must not be present, and removed components must be present.
+func (w *refWorld) Exchange(e ecs.Entity, add, rem uint8) {
+	ent := w.entities[e]
+	oldTarget := ent.target
+	ent.comps = (ent.comps &^ rem) | add
+	for k := 0; k < refKinds; k++ {
+		if (add|rem)&(1<<k) != 0 {
+			ent.values[k] = 0
+		}
+	}
+	if (add|rem)&(1<<refR) != 0 {
+		ent.target = ecs.Entity{}
+	}
+	w.events = append(w.events, refEvent{Entity: e, Added: add, Dropped: rem, OldTarget: oldTarget, NewTarget: ent.target})
+}
+
+// SetTarget sets the relation target of an alive entity that has the relation component.
+func (w *refWorld) SetTarget(e ecs.Entity, target ecs.Entity) {
+	ent := w.entities[e]
+	if ent.target == target {
+		return
+	}
+	w.events = append(w.events, refEvent{Entity: e, OldTarget: ent.target, NewTarget: target})
+	ent.target = target
+}
+
+// Query returns all alive entities that match a filter, in deterministic order.
+func (w *refWorld) Query(f *refFilter) []ecs.Entity {
+	result := []ecs.Entity{}
+	for _, e := range w.order {
+		if f.Matches(w.entities[e]) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Reset removes all entities. Emits no events.
+func (w *refWorld) Reset() {
+	clear(w.entities)
+	clear(w.used)
+	w.order = w.order[:0]
+}
+
+// TakeEvents returns the expected events of the current operation in sorted order, and clears them.
+func (w *refWorld) TakeEvents() []refEvent {
+	events := w.events
+	w.events = nil
+	sortRefEvents(events)
+	return events
+}
+
+// sortRefEvents sorts events by entity, for comparing events independent of their order.
+func sortRefEvents(events []refEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].Entity, events[j].Entity
+		if a.ID() != b.ID() {
+			return a.ID() < b.ID()
+		}
+		return a.Generation() < b.Generation()
+	})
+}
@@ -53,6 +53,10 @@ func TestRelationStress(t *testing.T) {
 
 			stats = world.Stats()
 			assert.Equal(t, numParents, stats.Nodes[2].ArchetypeCount)
+
+			if i%100 == 0 {
+				assert.NoError(t, world.Validate())
+			}
 		}
 	}
 }
@@ -0,0 +1,73 @@
+package ecs
+
+// Reserve reserves an entity, to be created later with [World.Materialize].
+//
+// Reserve is safe to call concurrently from multiple goroutines, also while the world is locked.
+// This allows parallel workers, e.g. during query iteration, to obtain entities for spawn decisions,
+// and to wire relations and references between entities that are not yet created.
+// It must not be called concurrently with other operations that modify the world.
+//
+// The reserved entity is not alive before it is materialized.
+// Thus, it can't be used with any other world operations before,
+// but it can be stored in components, or used as a relation target after materialization.
+// Each reserved entity should be materialized. Otherwise, its ID is not recycled before [World.Reset].
+//
+// Panics during a transaction, see [World.Begin].
+func (w *World) Reserve() Entity {
+	if w.tx != nil {
+		panic("can't reserve entities during a transaction")
+	}
+	return w.entityPool.Reserve()
+}
+
+// Materialize creates a previously reserved entity, with the given components.
+// See [World.Reserve].
+//
+// Panics:
+//   - when called on a locked world. Do not use during [Query] iteration!
+//   - when called for an entity that is not reserved, or that was already materialized.
+func (w *World) Materialize(entity Entity, comps ...ID) {
+	w.checkLocked()
+	w.flushReserved()
+
+	if !w.entityPool.IsReserved(entity) {
+		panic("can't materialize an entity that is not reserved")
+	}
+	if w.tx != nil {
+		w.tx.capture(entity.id)
+	}
+
+	arch := w.archetypes.Get(0)
+	if len(comps) > 0 {
+		arch = w.findOrCreateArchetype(arch, comps, nil, Entity{})
+	}
+
+	w.entityPool.Materialize(entity)
+	idx := arch.Alloc(entity)
+	w.applyDefaults(arch, idx, comps)
+	w.entities[entity.id] = entityIndex{arch: arch, index: idx}
+	w.targetEntities.Set(entity.id, false)
+
+	w.callHooks(hookAdd, entity, comps)
+	w.notifyCreated(entity, arch, comps)
+}
+
+// flushReserved allocates pool entries and entity indices for reserved entities.
+// Must be called before entities are created, so that reserved IDs are not handed out again.
+func (w *World) flushReserved() {
+	if len(w.entityPool.reserved) > 0 {
+		// outline to allow inlining of the fast path
+		w.flushReservedEntities()
+	}
+}
+
+// flushReservedEntities allocates pool entries and entity indices for reserved entities.
+func (w *World) flushReservedEntities() {
+	if !w.entityPool.Flush() {
+		return
+	}
+	if required := len(w.entityPool.entities); required > len(w.entities) {
+		w.entities = append(w.entities, make([]entityIndex, required-len(w.entities))...)
+		w.targetEntities.ExtendTo(cap(w.entities))
+	}
+}
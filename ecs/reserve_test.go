@@ -0,0 +1,164 @@
+package ecs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldReserve(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) { events = append(events, e) })
+	w.SetListener(&listener)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+	w.RemoveEntity(e1)
+	events = events[:0]
+
+	r1 := w.Reserve()
+	r2 := w.Reserve()
+	assert.Equal(t, Entity{1, 1}, r1)
+	assert.Equal(t, Entity{3, 0}, r2)
+	assert.False(t, w.Alive(r1))
+	assert.False(t, w.Alive(r2))
+	assert.NoError(t, w.Validate())
+
+	e3 := w.NewEntity(posID)
+	e4 := w.NewEntity()
+	assert.Equal(t, Entity{4, 0}, e3)
+	assert.Equal(t, Entity{5, 0}, e4)
+	assert.False(t, w.Alive(r2))
+	assert.NoError(t, w.Validate())
+	events = events[:0]
+
+	w.Materialize(r2, posID)
+	w.Materialize(r1, relID)
+	w.Relations().Set(r1, relID, r2)
+	assert.True(t, w.Alive(r1))
+	assert.True(t, w.Alive(r2))
+	assert.True(t, w.Has(r2, posID))
+	assert.Equal(t, r2, w.Relations().Get(r1, relID))
+	assert.NoError(t, w.Validate())
+
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, r2, events[0].Entity)
+	assert.Equal(t, []ID{posID}, events[0].AddedIDs)
+	assert.True(t, events[0].Contains(event.EntityCreated))
+	assert.Equal(t, r1, events[1].Entity)
+	assert.True(t, events[1].Contains(event.EntityCreated))
+
+	assert.PanicsWithValue(t, "can't materialize an entity that is not reserved", func() { w.Materialize(r1) })
+	assert.PanicsWithValue(t, "can't materialize an entity that is not reserved", func() { w.Materialize(e2) })
+	assert.PanicsWithValue(t, "can't materialize an entity that is not reserved", func() { w.Materialize(e1) })
+	assert.PanicsWithValue(t, "can't materialize an entity that is not reserved", func() { w.Materialize(Entity{}) })
+
+	r3 := w.Reserve()
+	w.Batch().New(10, posID)
+	assert.False(t, w.Alive(r3))
+	assert.NoError(t, w.Validate())
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Materialize(r3) })
+	query.Close()
+	w.Materialize(r3)
+	assert.True(t, w.Alive(r3))
+	assert.Equal(t, 16, w.Stats().Entities.Used)
+
+	r5 := w.Reserve()
+	dump := w.DumpEntities()
+	w2 := NewWorld()
+	w2.LoadEntities(&dump)
+	assert.NoError(t, w2.Validate())
+	assert.False(t, w2.Alive(r5))
+	w2.Materialize(r5)
+	assert.True(t, w2.Alive(r5))
+	assert.NoError(t, w2.Validate())
+
+	r4 := w.Reserve()
+	w.Reset()
+	assert.False(t, w.Alive(r4))
+	assert.PanicsWithValue(t, "can't materialize an entity that is not reserved", func() { w.Materialize(r4) })
+	assert.Equal(t, Entity{1, 0}, w.NewEntity())
+	assert.NoError(t, w.Validate())
+}
+
+func TestWorldReserveConcurrent(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parents := []Entity{}
+	for i := 0; i < 100; i++ {
+		parents = append(parents, w.NewEntity(posID))
+	}
+	for _, e := range parents[:50] {
+		w.RemoveEntity(e)
+	}
+
+	workers := 8
+	perWorker := 25
+	reserved := make([][]Entity, workers)
+
+	query := w.Query(All(posID))
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				e := w.Reserve()
+				assert.False(t, w.Alive(e))
+				reserved[i] = append(reserved[i], e)
+			}
+		}(i)
+	}
+	wg.Wait()
+	query.Close()
+
+	unique := map[Entity]bool{}
+	for _, entities := range reserved {
+		for _, e := range entities {
+			unique[e] = true
+		}
+	}
+	assert.Equal(t, workers*perWorker, len(unique))
+
+	for _, entities := range reserved {
+		parent := entities[0]
+		w.Materialize(parent, posID)
+		for _, e := range entities[1:] {
+			w.Materialize(e, relID)
+			w.Relations().Set(e, relID, parent)
+		}
+	}
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, 50+workers*perWorker, w.Stats().Entities.Used)
+	assert.Equal(t, 0, w.Stats().Entities.Recycled)
+}
+
+func TestWorldReserveTx(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	r := w.Reserve()
+	tx := w.Begin()
+	assert.PanicsWithValue(t, "can't reserve entities during a transaction", func() { w.Reserve() })
+
+	w.Materialize(r, posID)
+	assert.True(t, w.Alive(r))
+	tx.Rollback()
+
+	assert.False(t, w.Alive(r))
+	assert.NoError(t, w.Validate())
+
+	w.Materialize(r, posID)
+	assert.True(t, w.Alive(r))
+	assert.NoError(t, w.Validate())
+}
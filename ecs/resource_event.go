@@ -0,0 +1,58 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/event"
+
+// ResourceEvent contains information about resource addition, removal and changes.
+//
+// To receive resource events, register a [ResourceListener] with [World.SetResourceListener].
+//
+// # Event scheduling
+//
+// Events for added resources are emitted right after the resource was added.
+// Events for removed resources are emitted right before the resource is removed,
+// to allow for inspection of the resource.
+// Events for changed resources are emitted after the resource was replaced with [Resources.Set].
+//
+// No events are emitted on [World.Reset].
+type ResourceEvent struct {
+	Resource   ResID                      // The affected resource.
+	EventTypes event.ResourceSubscription // Bit mask of event types. See [event.ResourceSubscription].
+}
+
+// Contains returns whether the event's types contain the given type/subscription bit.
+func (e *ResourceEvent) Contains(bit event.ResourceSubscription) bool {
+	return e.EventTypes.Contains(bit)
+}
+
+// ResourceListener interface for listening to [ResourceEvent] notifications
+// on resource addition, removal and changes.
+//
+// A resource listener can be added to a [World] with [World.SetResourceListener].
+//
+// # Subscriptions
+//
+// Resource listeners can subscribe to one or more event types via method Subscriptions.
+// Further, subscriptions can be restricted to one or more resources via method Resources.
+// Create the resources mask with [ResourceMask].
+//
+// See sub-package [event] and the [event.ResourceSubscription] constants for event types.
+// See package [github.com/mlange-42/arche/listener] for ResourceListener implementations.
+type ResourceListener interface {
+	// Notify the listener about a subscribed event.
+	Notify(world *World, evt ResourceEvent)
+	// Subscriptions to one or more event types.
+	Subscriptions() event.ResourceSubscription
+	// Resources the listener subscribes to. Listening to all resources indicated by nil.
+	Resources() *Mask
+}
+
+// ResourceMask creates a [Mask] of resource IDs, for use in [ResourceListener].Resources.
+//
+// Note that resource masks are not related to component masks, and must not be used for filtering entities.
+func ResourceMask(ids ...ResID) Mask {
+	mask := Mask{}
+	for _, r := range ids {
+		mask.Set(id(r.id), true)
+	}
+	return mask
+}
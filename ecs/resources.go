@@ -3,6 +3,8 @@ package ecs
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/mlange-42/arche/ecs/event"
 )
 
 // Resources manage a world's resources.
@@ -11,6 +13,8 @@ import (
 type Resources struct {
 	resources []any
 	registry  registry
+	listener  ResourceListener // Resource event listener.
+	world     *World           // The world, passed to the listener.
 }
 
 // newResources creates a new Resources manager.
@@ -32,6 +36,7 @@ func (r *Resources) Add(id ResID, res any) {
 		panic(fmt.Sprintf("Resource of ID %d was already added (type %v)", id.id, reflect.TypeOf(res)))
 	}
 	r.resources[id.id] = res
+	r.notify(id, event.ResourceAdded)
 }
 
 // Remove a resource from the world.
@@ -43,9 +48,26 @@ func (r *Resources) Remove(id ResID) {
 	if r.resources[id.id] == nil {
 		panic(fmt.Sprintf("Resource of ID %d is not present", id.id))
 	}
+	r.notify(id, event.ResourceRemoved)
 	r.resources[id.id] = nil
 }
 
+// Set replaces a resource of the world, and notifies the [ResourceListener] about the change.
+// The resource should always be a pointer.
+//
+// Can also be called with the current resource, to notify about in-place modifications.
+//
+// Panics if there is no resource of the given type.
+//
+// See also [github.com/mlange-42/arche/generic.Resource.Set] for a generic variant.
+func (r *Resources) Set(id ResID, res any) {
+	if r.resources[id.id] == nil {
+		panic(fmt.Sprintf("Resource of ID %d is not present", id.id))
+	}
+	r.resources[id.id] = res
+	r.notify(id, event.ResourceChanged)
+}
+
 // Get returns a pointer to the resource of the given type.
 //
 // Returns nil if there is no such resource.
@@ -62,6 +84,20 @@ func (r *Resources) Has(id ResID) bool {
 	return r.resources[id.id] != nil
 }
 
+// notify the listener about a resource event.
+func (r *Resources) notify(id ResID, bits event.ResourceSubscription) {
+	if r.listener == nil {
+		return
+	}
+	if r.listener.Subscriptions()&bits == 0 {
+		return
+	}
+	if subs := r.listener.Resources(); subs != nil && !subs.Get(ID{id: id.id}) {
+		return
+	}
+	r.listener.Notify(r.world, ResourceEvent{Resource: id, EventTypes: bits})
+}
+
 // reset removes all resources.
 func (r *Resources) reset() {
 	for i := range r.resources {
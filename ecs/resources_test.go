@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/mlange-42/arche/ecs/event"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -75,6 +76,72 @@ func TestResourcesReset(t *testing.T) {
 	assert.Equal(t, rotation{50}, *rot)
 }
 
+type testResourceListener struct {
+	events    []ResourceEvent
+	subs      event.ResourceSubscription
+	resources *Mask
+	checkRes  func(w *World, e ResourceEvent)
+}
+
+func (l *testResourceListener) Notify(w *World, e ResourceEvent) {
+	if l.checkRes != nil {
+		l.checkRes(w, e)
+	}
+	l.events = append(l.events, e)
+}
+
+func (l *testResourceListener) Subscriptions() event.ResourceSubscription { return l.subs }
+
+func (l *testResourceListener) Resources() *Mask { return l.resources }
+
+func TestResourcesListener(t *testing.T) {
+	w := NewWorld()
+	posID := ResourceID[Position](&w)
+	rotID := ResourceID[rotation](&w)
+
+	ls := testResourceListener{subs: event.Resources}
+	ls.checkRes = func(world *World, e ResourceEvent) {
+		assert.Equal(t, &w, world)
+		// Resources are present during all events, also before removal.
+		assert.True(t, world.Resources().Has(e.Resource))
+	}
+	w.SetResourceListener(&ls)
+
+	w.Resources().Add(posID, &Position{})
+	w.Resources().Add(rotID, &rotation{})
+	w.Resources().Set(posID, &Position{1, 2})
+	w.Resources().Remove(rotID)
+
+	assert.Equal(t, []ResourceEvent{
+		{Resource: posID, EventTypes: event.ResourceAdded},
+		{Resource: rotID, EventTypes: event.ResourceAdded},
+		{Resource: posID, EventTypes: event.ResourceChanged},
+		{Resource: rotID, EventTypes: event.ResourceRemoved},
+	}, ls.events)
+	assert.Equal(t, Position{1, 2}, *w.Resources().Get(posID).(*Position))
+	assert.Panics(t, func() { w.Resources().Set(rotID, &rotation{}) })
+
+	mask := ResourceMask(rotID)
+	ls = testResourceListener{subs: event.ResourceAdded | event.ResourceChanged, resources: &mask}
+	w.SetResourceListener(&ls)
+
+	w.Resources().Set(posID, &Position{})
+	w.Resources().Add(rotID, &rotation{})
+	w.Resources().Set(rotID, &rotation{})
+	w.Resources().Remove(rotID)
+	assert.Equal(t, []ResourceEvent{
+		{Resource: rotID, EventTypes: event.ResourceAdded},
+		{Resource: rotID, EventTypes: event.ResourceChanged},
+	}, ls.events)
+
+	w.Reset()
+	assert.Equal(t, 2, len(ls.events))
+
+	w.SetResourceListener(nil)
+	w.Resources().Add(rotID, &rotation{})
+	assert.Equal(t, 2, len(ls.events))
+}
+
 func ExampleResources() {
 	world := NewWorld()
 
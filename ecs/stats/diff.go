@@ -0,0 +1,182 @@
+package stats
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WorldDiff reports the differences between two [World] statistics snapshots.
+// All values are deltas from the first to the second snapshot.
+//
+// See [Diff].
+type WorldDiff struct {
+	// Delta of used/alive entities.
+	Entities int
+	// Delta of recycled/available entities.
+	Recycled int
+	// Delta of the number of nodes.
+	NodeCount int
+	// Delta of the number of active nodes.
+	ActiveNodeCount int
+	// Delta of the total number of archetypes, incl. inactive.
+	ArchetypeCount int
+	// Delta of the number of active archetypes.
+	ActiveArchetypeCount int
+	// Delta of the memory used by entities and components, in bytes.
+	Memory int
+	// Delta of the number of cached filters.
+	CachedFilters int
+	// Differences per node, i.e. per component set. Contains only nodes with changes.
+	Nodes []NodeDiff
+}
+
+// NodeDiff reports the differences of an archetype graph node between two snapshots.
+type NodeDiff struct {
+	// Component IDs of the node.
+	ComponentIDs []uint8
+	// Component types for ComponentIDs.
+	ComponentTypes []reflect.Type
+	// Delta of the number of entities.
+	Size int
+	// Delta of the total number of archetypes, incl. inactive.
+	ArchetypeCount int
+	// Delta of the number of active archetypes.
+	ActiveArchetypeCount int
+	// Delta of the sum of capacity of the archetypes.
+	Capacity int
+	// Delta of the reserved memory, in bytes.
+	Memory int
+}
+
+// Diff reports the differences between two [World] statistics snapshots a and b.
+//
+// Nodes are matched by their component sets.
+// Nodes that are only present in one of the snapshots are compared against an empty node.
+//
+// Note that [github.com/mlange-42/arche/ecs.World.Stats] re-uses the returned object between calls.
+// Use [World.Clone] to keep a snapshot for later comparison.
+func Diff(a, b *World) WorldDiff {
+	diff := WorldDiff{
+		Entities:             b.Entities.Used - a.Entities.Used,
+		Recycled:             b.Entities.Recycled - a.Entities.Recycled,
+		NodeCount:            len(b.Nodes) - len(a.Nodes),
+		ActiveNodeCount:      b.ActiveNodeCount - a.ActiveNodeCount,
+		ArchetypeCount:       b.archetypeCount() - a.archetypeCount(),
+		ActiveArchetypeCount: b.activeArchetypeCount() - a.activeArchetypeCount(),
+		Memory:               b.Memory - a.Memory,
+		CachedFilters:        b.CachedFilters - a.CachedFilters,
+	}
+
+	nodesA := make(map[string]*Node, len(a.Nodes))
+	for i := range a.Nodes {
+		nodesA[nodeKey(&a.Nodes[i])] = &a.Nodes[i]
+	}
+
+	empty := Node{}
+	for i := range b.Nodes {
+		nodeB := &b.Nodes[i]
+		key := nodeKey(nodeB)
+		nodeA, ok := nodesA[key]
+		if ok {
+			delete(nodesA, key)
+		} else {
+			nodeA = &empty
+		}
+		if d, changed := diffNode(nodeA, nodeB, nodeB); changed {
+			diff.Nodes = append(diff.Nodes, d)
+		}
+	}
+	// Nodes that are only present in a.
+	for i := range a.Nodes {
+		nodeA := &a.Nodes[i]
+		if _, ok := nodesA[nodeKey(nodeA)]; !ok {
+			continue
+		}
+		if d, changed := diffNode(nodeA, &empty, nodeA); changed {
+			diff.Nodes = append(diff.Nodes, d)
+		}
+	}
+
+	return diff
+}
+
+// diffNode calculates the difference between two nodes.
+// Argument ref provides the component IDs and types.
+func diffNode(a, b, ref *Node) (NodeDiff, bool) {
+	d := NodeDiff{
+		ComponentIDs:         ref.ComponentIDs,
+		ComponentTypes:       ref.ComponentTypes,
+		Size:                 b.Size - a.Size,
+		ArchetypeCount:       b.ArchetypeCount - a.ArchetypeCount,
+		ActiveArchetypeCount: b.ActiveArchetypeCount - a.ActiveArchetypeCount,
+		Capacity:             b.Capacity - a.Capacity,
+		Memory:               b.Memory - a.Memory,
+	}
+	changed := d.Size != 0 || d.ArchetypeCount != 0 || d.ActiveArchetypeCount != 0 || d.Capacity != 0 || d.Memory != 0
+	return d, changed
+}
+
+// nodeKey returns a unique key for the component set of a node.
+func nodeKey(node *Node) string {
+	return string(node.ComponentIDs)
+}
+
+// archetypeCount returns the total number of archetypes.
+func (s *World) archetypeCount() int {
+	count := 0
+	for i := range s.Nodes {
+		count += s.Nodes[i].ArchetypeCount
+	}
+	return count
+}
+
+// activeArchetypeCount returns the number of active archetypes.
+func (s *World) activeArchetypeCount() int {
+	count := 0
+	for i := range s.Nodes {
+		count += s.Nodes[i].ActiveArchetypeCount
+	}
+	return count
+}
+
+// Clone returns a deep copy of the statistics.
+func (s *World) Clone() World {
+	c := *s
+	c.ComponentTypes = append([]reflect.Type{}, s.ComponentTypes...)
+	c.Components = append([]Component{}, s.Components...)
+	c.Queries = append([]Query{}, s.Queries...)
+	c.Nodes = make([]Node, len(s.Nodes))
+	for i := range s.Nodes {
+		node := s.Nodes[i]
+		node.ComponentIDs = append([]uint8{}, node.ComponentIDs...)
+		node.ComponentTypes = append([]reflect.Type{}, node.ComponentTypes...)
+		node.Archetypes = append([]Archetype{}, node.Archetypes...)
+		c.Nodes[i] = node
+	}
+	return c
+}
+
+func (d *WorldDiff) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(
+		&b, "Diff -- Entities: %+d, Recycled: %+d, Nodes: %+d (active %+d), Archetypes: %+d (active %+d), Memory: %+.1f kB, Filters: %+d\n",
+		d.Entities, d.Recycled, d.NodeCount, d.ActiveNodeCount,
+		d.ArchetypeCount, d.ActiveArchetypeCount, float64(d.Memory)/1024.0, d.CachedFilters,
+	)
+	for i := range d.Nodes {
+		fmt.Fprint(&b, d.Nodes[i].String())
+	}
+	return b.String()
+}
+
+func (d *NodeDiff) String() string {
+	typeNames := make([]string, len(d.ComponentTypes))
+	for i, tp := range d.ComponentTypes {
+		typeNames[i] = tp.Name()
+	}
+	return fmt.Sprintf(
+		"Node -- Entities: %+6d, Archetypes: %+4d (active %+4d), Capacity: %+6d, Memory: %+7.1f kB\n  Components: %s\n",
+		d.Size, d.ArchetypeCount, d.ActiveArchetypeCount, d.Capacity, float64(d.Memory)/1024.0, strings.Join(typeNames, ", "),
+	)
+}
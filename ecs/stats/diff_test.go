@@ -0,0 +1,90 @@
+package stats_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+type ChildOf struct {
+	ecs.Relation
+}
+
+func TestDiff(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+	relID := ecs.ComponentID[ChildOf](&world)
+
+	world.Batch().New(10, posID)
+	before := world.Stats().Clone()
+
+	diff := stats.Diff(&before, world.Stats())
+	assert.Equal(t, stats.WorldDiff{}, diff)
+
+	world.Batch().New(5, posID, velID)
+	parent1 := world.NewEntity()
+	parent2 := world.NewEntity()
+	builder := ecs.NewBuilder(&world, relID).WithRelation(relID)
+	builder.NewBatch(3, parent1)
+	builder.NewBatch(3, parent2)
+
+	after := world.Stats()
+	diff = stats.Diff(&before, after)
+	assert.Equal(t, 5+2+6, diff.Entities)
+	assert.Equal(t, 2, diff.NodeCount)
+	assert.Equal(t, 2, diff.ActiveNodeCount)
+	assert.Equal(t, 1+2, diff.ArchetypeCount)
+	assert.Equal(t, 3, diff.ActiveArchetypeCount)
+	assert.Greater(t, diff.Memory, 0)
+
+	assert.Equal(t, 3, len(diff.Nodes))
+	for _, n := range diff.Nodes {
+		if len(n.ComponentIDs) == 1 && n.ComponentTypes[0].Name() == "ChildOf" {
+			assert.Equal(t, 6, n.Size)
+			assert.Equal(t, 2, n.ArchetypeCount)
+		}
+	}
+
+	reverse := stats.Diff(after, &before)
+	assert.Equal(t, -diff.Entities, reverse.Entities)
+	assert.Equal(t, -diff.ArchetypeCount, reverse.ArchetypeCount)
+	assert.Equal(t, len(diff.Nodes), len(reverse.Nodes))
+
+	fmt.Println(diff.String())
+}
+
+func TestClone(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	world.Batch().New(1, posID)
+	clone := world.Stats().Clone()
+	assert.Equal(t, 1, clone.Components[0].Entities)
+
+	world.Batch().New(10, posID)
+	assert.Equal(t, 11, world.Stats().Components[0].Entities)
+	assert.Equal(t, 1, clone.Components[0].Entities)
+
+	original := stats.World{Queries: []stats.Query{{Filter: "All(Position)", Queries: 1}}}
+	clone = original.Clone()
+	original.Queries[0].Queries = 2
+	assert.Equal(t, 1, clone.Queries[0].Queries)
+}
+
+func ExampleDiff() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	// Keep a copy, as the stats object is re-used by the world.
+	before := world.Stats().Clone()
+
+	world.Batch().New(100, posID)
+
+	diff := stats.Diff(&before, world.Stats())
+	fmt.Println(diff.Entities, diff.ActiveNodeCount)
+	// Output: 100 1
+}
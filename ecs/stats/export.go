@@ -0,0 +1,200 @@
+package stats
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Exporter publishes world statistics via [expvar] and in the Prometheus text exposition format.
+//
+// Statistics are obtained lazily from a source function on every scrape,
+// typically the method value of [github.com/mlange-42/arche/ecs.World.Stats]:
+//
+//	exporter := stats.NewExporter(world.Stats)
+//	http.Handle("/metrics", exporter)
+//
+// ⚠️ Important: The source is called from the goroutine that serves the request.
+// As the world is not thread-safe, the source must be synchronized with the world's updates,
+// e.g. by wrapping it in a function that holds a mutex that is also held while updating the world.
+type Exporter struct {
+	source func() *World
+}
+
+// NewExporter creates a new [Exporter] for the given statistics source.
+func NewExporter(source func() *World) *Exporter {
+	return &Exporter{source: source}
+}
+
+// Var returns an [expvar.Var] that renders the statistics as JSON.
+func (e *Exporter) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return newExpvarWorld(e.source())
+	})
+}
+
+// Publish publishes the statistics via [expvar] under the given name.
+//
+// Panics if the name is already registered, like [expvar.Publish].
+func (e *Exporter) Publish(name string) {
+	expvar.Publish(name, e.Var())
+}
+
+// ServeHTTP serves the statistics in the Prometheus text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := e.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WritePrometheus writes the statistics in the Prometheus text exposition format.
+//
+// All metrics are gauges with prefix "arche_".
+// Per-node metrics are written for active nodes only, labeled by their component types.
+// Per-component metrics are labeled by the component type.
+func (e *Exporter) WritePrometheus(w io.Writer) error {
+	s := e.source()
+	b := bufio.NewWriter(w)
+
+	locked := 0
+	if s.Locked {
+		locked = 1
+	}
+	archetypes := 0
+	for i := range s.Nodes {
+		archetypes += s.Nodes[i].ActiveArchetypeCount
+	}
+
+	writeGauge(b, "arche_entities_used", "Number of alive entities.", s.Entities.Used)
+	writeGauge(b, "arche_entities_recycled", "Number of recycled entities available for reuse.", s.Entities.Recycled)
+	writeGauge(b, "arche_entities_total", "Current capacity of the entity pool.", s.Entities.Total)
+	writeGauge(b, "arche_entities_capacity", "Current capacity of the entities list.", s.Entities.Capacity)
+	writeGauge(b, "arche_entities_named", "Number of named entities.", s.Entities.Named)
+	writeGauge(b, "arche_components", "Number of registered component types.", s.ComponentCount)
+	writeGauge(b, "arche_nodes", "Number of archetype graph nodes.", len(s.Nodes))
+	writeGauge(b, "arche_nodes_active", "Number of active archetype graph nodes.", s.ActiveNodeCount)
+	writeGauge(b, "arche_archetypes_active", "Number of active archetypes.", archetypes)
+	writeGauge(b, "arche_memory_bytes", "Memory reserved for entities and components, in bytes.", s.Memory)
+	writeGauge(b, "arche_cached_filters", "Number of cached filters.", s.CachedFilters)
+	writeGauge(b, "arche_locked", "Whether the world is locked (1) or not (0).", locked)
+
+	writeNodeGauge(b, s.Nodes, "arche_node_entities", "Number of entities per archetype graph node.",
+		func(n *Node) int { return n.Size })
+	writeNodeGauge(b, s.Nodes, "arche_node_capacity", "Capacity per archetype graph node.",
+		func(n *Node) int { return n.Capacity })
+	writeNodeGauge(b, s.Nodes, "arche_node_memory_bytes", "Memory reserved per archetype graph node, in bytes.",
+		func(n *Node) int { return n.Memory })
+	writeNodeGauge(b, s.Nodes, "arche_node_archetypes", "Number of active archetypes per archetype graph node.",
+		func(n *Node) int { return n.ActiveArchetypeCount })
+
+	writeComponentGauge(b, s.Components, "arche_component_entities", "Number of entities per component type.",
+		func(c *Component) int { return c.Entities })
+	writeComponentGauge(b, s.Components, "arche_component_archetypes", "Number of active archetypes per component type.",
+		func(c *Component) int { return c.Archetypes })
+	writeComponentGauge(b, s.Components, "arche_component_memory_bytes", "Memory reserved per component type, in bytes.",
+		func(c *Component) int { return c.Memory })
+
+	return b.Flush()
+}
+
+// writeGauge writes a single gauge metric without labels.
+func writeGauge(w io.Writer, name, help string, value int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+}
+
+// writeNodeGauge writes a gauge metric with one sample per active node.
+func writeNodeGauge(w io.Writer, nodes []Node, name, help string, value func(*Node) int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	for i := range nodes {
+		node := &nodes[i]
+		if !node.IsActive {
+			continue
+		}
+		fmt.Fprintf(w, "%s{components=\"%s\"} %d\n", name, escapeLabel(nodeLabel(node)), value(node))
+	}
+}
+
+// writeComponentGauge writes a gauge metric with one sample per component type.
+func writeComponentGauge(w io.Writer, comps []Component, name, help string, value func(*Component) int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	for i := range comps {
+		comp := &comps[i]
+		fmt.Fprintf(w, "%s{component=\"%s\"} %d\n", name, escapeLabel(comp.Type.String()), value(comp))
+	}
+}
+
+// nodeLabel returns the comma-separated component types of a node.
+func nodeLabel(node *Node) string {
+	names := make([]string, len(node.ComponentTypes))
+	for i, tp := range node.ComponentTypes {
+		names[i] = tp.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// expvarWorld is the JSON representation of [World] for expvar.
+type expvarWorld struct {
+	Entities       expvarEntity `json:"entities"`
+	ComponentCount int          `json:"components"`
+	Nodes          int          `json:"nodes"`
+	ActiveNodes    int          `json:"active_nodes"`
+	Memory         int          `json:"memory"`
+	CachedFilters  int          `json:"cached_filters"`
+	Locked         bool         `json:"locked"`
+	NodeStats      []expvarNode `json:"node_stats"`
+}
+
+// expvarEntity is the JSON representation of [Entities] for expvar.
+type expvarEntity struct {
+	Used     int `json:"used"`
+	Total    int `json:"total"`
+	Recycled int `json:"recycled"`
+	Capacity int `json:"capacity"`
+	Named    int `json:"named"`
+}
+
+// expvarNode is the JSON representation of [Node] for expvar.
+type expvarNode struct {
+	Components string `json:"components"`
+	Archetypes int    `json:"archetypes"`
+	Size       int    `json:"size"`
+	Capacity   int    `json:"capacity"`
+	Memory     int    `json:"memory"`
+}
+
+// newExpvarWorld converts [World] statistics to their JSON representation.
+func newExpvarWorld(s *World) expvarWorld {
+	nodes := make([]expvarNode, 0, s.ActiveNodeCount)
+	for i := range s.Nodes {
+		node := &s.Nodes[i]
+		if !node.IsActive {
+			continue
+		}
+		nodes = append(nodes, expvarNode{
+			Components: nodeLabel(node),
+			Archetypes: node.ActiveArchetypeCount,
+			Size:       node.Size,
+			Capacity:   node.Capacity,
+			Memory:     node.Memory,
+		})
+	}
+	return expvarWorld{
+		Entities:       expvarEntity(s.Entities),
+		ComponentCount: s.ComponentCount,
+		Nodes:          len(s.Nodes),
+		ActiveNodes:    s.ActiveNodeCount,
+		Memory:         s.Memory,
+		CachedFilters:  s.CachedFilters,
+		Locked:         s.Locked,
+		NodeStats:      nodes,
+	}
+}
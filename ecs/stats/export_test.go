@@ -0,0 +1,105 @@
+package stats_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X, Y float64
+}
+
+type Velocity struct {
+	X, Y float64
+}
+
+func TestExporterPrometheus(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	exporter := stats.NewExporter(world.Stats)
+	server := httptest.NewServer(exporter)
+	defer server.Close()
+
+	scrape := func() string {
+		resp, err := server.Client().Get(server.URL)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"))
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return string(body)
+	}
+
+	text := scrape()
+	assert.Contains(t, text, "# TYPE arche_entities_used gauge\narche_entities_used 0\n")
+	assert.Contains(t, text, "arche_components 2\n")
+	assert.Contains(t, text, "arche_locked 0\n")
+
+	world.Batch().New(10, posID)
+	world.Batch().New(5, posID, velID)
+
+	text = scrape()
+	assert.Contains(t, text, "arche_entities_used 15\n")
+	assert.Contains(t, text, "arche_nodes_active 3\n")
+	assert.Contains(t, text, "arche_node_entities{components=\"stats_test.Position\"} 10\n")
+	assert.Contains(t, text, "arche_node_entities{components=\"stats_test.Position,stats_test.Velocity\"} 5\n")
+	assert.Contains(t, text, "arche_component_entities{component=\"stats_test.Position\"} 15\n")
+	assert.Contains(t, text, "arche_component_archetypes{component=\"stats_test.Velocity\"} 1\n")
+
+	query := world.Query(ecs.All())
+	text = scrape()
+	assert.Contains(t, text, "arche_locked 1\n")
+	query.Close()
+}
+
+func TestExporterExpvar(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	exporter := stats.NewExporter(world.Stats)
+	exporter.Publish("arche_test_world")
+	assert.Panics(t, func() { exporter.Publish("arche_test_world") })
+
+	world.Batch().New(10, posID)
+
+	v := expvar.Get("arche_test_world")
+	data := map[string]any{}
+	err := json.Unmarshal([]byte(v.String()), &data)
+	assert.Nil(t, err)
+
+	entities := data["entities"].(map[string]any)
+	assert.Equal(t, 10.0, entities["used"])
+	assert.Equal(t, 1.0, data["components"])
+	assert.Equal(t, 2, len(data["node_stats"].([]any)))
+}
+
+func ExampleExporter() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	world.Batch().New(100, posID)
+
+	exporter := stats.NewExporter(world.Stats)
+
+	// Serve via http.Handle("/metrics", exporter), or write directly.
+	b := strings.Builder{}
+	_ = exporter.WritePrometheus(&b)
+
+	for _, line := range strings.Split(b.String(), "\n") {
+		if strings.HasPrefix(line, "arche_entities_used") {
+			fmt.Println(line)
+		}
+	}
+	// Output: arche_entities_used 100
+}
@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Sample is a compact summary of [World] statistics at a certain tick, as recorded by a [Recorder].
+type Sample struct {
+	// Tick of the sample.
+	Tick int `json:"tick"`
+	// Used/alive entities.
+	Entities int `json:"entities"`
+	// Recycled/available entities.
+	Recycled int `json:"recycled"`
+	// Number of nodes.
+	NodeCount int `json:"nodes"`
+	// Number of active nodes.
+	ActiveNodeCount int `json:"active_nodes"`
+	// Total number of archetypes, incl. inactive.
+	ArchetypeCount int `json:"archetypes"`
+	// Number of active archetypes.
+	ActiveArchetypeCount int `json:"active_archetypes"`
+	// Memory used by entities and components, in bytes.
+	Memory int `json:"memory"`
+	// Number of cached filters.
+	CachedFilters int `json:"cached_filters"`
+}
+
+// NewSample creates a [Sample] from [World] statistics.
+func NewSample(tick int, s *World) Sample {
+	return Sample{
+		Tick:                 tick,
+		Entities:             s.Entities.Used,
+		Recycled:             s.Entities.Recycled,
+		NodeCount:            len(s.Nodes),
+		ActiveNodeCount:      s.ActiveNodeCount,
+		ArchetypeCount:       s.archetypeCount(),
+		ActiveArchetypeCount: s.activeArchetypeCount(),
+		Memory:               s.Memory,
+		CachedFilters:        s.CachedFilters,
+	}
+}
+
+// Recorder samples world statistics at regular intervals into a ring buffer.
+//
+// Statistics are obtained from a source function,
+// typically the method value of [github.com/mlange-42/arche/ecs.World.Stats]:
+//
+//	recorder := stats.NewRecorder(world.Stats, 10, 1000)
+//	for {
+//	    // ... update the world
+//	    recorder.Tick()
+//	}
+//
+// When the buffer is full, the oldest samples are overwritten.
+type Recorder struct {
+	source   func() *World
+	samples  []Sample
+	start    int
+	len      int
+	interval int
+	tick     int
+}
+
+// NewRecorder creates a new [Recorder] that samples every interval ticks,
+// and keeps at most capacity samples.
+//
+// Panics if interval or capacity are not positive.
+func NewRecorder(source func() *World, interval int, capacity int) *Recorder {
+	if interval <= 0 {
+		panic("recorder interval must be positive")
+	}
+	if capacity <= 0 {
+		panic("recorder capacity must be positive")
+	}
+	return &Recorder{
+		source:   source,
+		samples:  make([]Sample, capacity),
+		interval: interval,
+	}
+}
+
+// Tick advances the recorder by one tick, and takes a sample every interval ticks.
+// The first call takes a sample at tick 0.
+func (r *Recorder) Tick() {
+	if r.tick%r.interval == 0 {
+		r.Record()
+	}
+	r.tick++
+}
+
+// Record takes a sample at the current tick, independent of the interval.
+func (r *Recorder) Record() {
+	sample := NewSample(r.tick, r.source())
+	idx := (r.start + r.len) % len(r.samples)
+	r.samples[idx] = sample
+	if r.len < len(r.samples) {
+		r.len++
+	} else {
+		r.start = (r.start + 1) % len(r.samples)
+	}
+}
+
+// Len returns the number of samples currently stored.
+func (r *Recorder) Len() int {
+	return r.len
+}
+
+// Get returns the sample at the given index, with index 0 being the oldest stored sample.
+// Panics if the index is out of range.
+func (r *Recorder) Get(index int) Sample {
+	if index < 0 || index >= r.len {
+		panic("recorder index out of range")
+	}
+	return r.samples[(r.start+index)%len(r.samples)]
+}
+
+// Samples returns a copy of all stored samples, from oldest to newest.
+func (r *Recorder) Samples() []Sample {
+	samples := make([]Sample, r.len)
+	for i := range samples {
+		samples[i] = r.Get(i)
+	}
+	return samples
+}
+
+// Reset removes all samples, and resets the tick counter.
+func (r *Recorder) Reset() {
+	r.start = 0
+	r.len = 0
+	r.tick = 0
+}
+
+// WriteJSON writes all stored samples as a JSON array, from oldest to newest.
+func (r *Recorder) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r.Samples())
+}
+
+// WriteCSV writes all stored samples as CSV with a header line, from oldest to newest.
+func (r *Recorder) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"tick", "entities", "recycled", "nodes", "active_nodes",
+		"archetypes", "active_archetypes", "memory", "cached_filters",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for i := 0; i < r.len; i++ {
+		s := r.Get(i)
+		values := []int{
+			s.Tick, s.Entities, s.Recycled, s.NodeCount, s.ActiveNodeCount,
+			s.ArchetypeCount, s.ActiveArchetypeCount, s.Memory, s.CachedFilters,
+		}
+		record := make([]string, len(values))
+		for j, v := range values {
+			record[j] = strconv.Itoa(v)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
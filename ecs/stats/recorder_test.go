@@ -0,0 +1,80 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	recorder := stats.NewRecorder(world.Stats, 2, 3)
+	assert.Equal(t, 0, recorder.Len())
+
+	for i := 0; i < 10; i++ {
+		world.NewEntity(posID)
+		recorder.Tick()
+	}
+	assert.Equal(t, 3, recorder.Len())
+
+	samples := recorder.Samples()
+	assert.Equal(t, []int{4, 6, 8}, []int{samples[0].Tick, samples[1].Tick, samples[2].Tick})
+	assert.Equal(t, 5, samples[0].Entities)
+	assert.Equal(t, 9, samples[2].Entities)
+	assert.Equal(t, samples[2], recorder.Get(2))
+	assert.Panics(t, func() { recorder.Get(3) })
+
+	recorder.Record()
+	assert.Equal(t, 10, recorder.Get(2).Tick)
+	assert.Equal(t, 10, recorder.Get(2).Entities)
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, recorder.WriteJSON(&buf))
+	decoded := []stats.Sample{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, recorder.Samples(), decoded)
+
+	buf.Reset()
+	assert.Nil(t, recorder.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, "tick,entities,recycled,nodes,active_nodes,archetypes,active_archetypes,memory,cached_filters", lines[0])
+	assert.True(t, strings.HasPrefix(lines[3], "10,10,0,2,2,2,2,"))
+
+	recorder.Reset()
+	assert.Equal(t, 0, recorder.Len())
+
+	assert.Panics(t, func() { stats.NewRecorder(world.Stats, 0, 10) })
+	assert.Panics(t, func() { stats.NewRecorder(world.Stats, 1, 0) })
+}
+
+func ExampleRecorder() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	// Sample every 10 ticks, keep the last 100 samples.
+	recorder := stats.NewRecorder(world.Stats, 10, 100)
+
+	for i := 0; i < 30; i++ {
+		world.NewEntity(posID)
+		recorder.Tick()
+	}
+
+	for _, s := range recorder.Samples() {
+		fmt.Printf("Tick: %d, Entities: %d\n", s.Tick, s.Entities)
+	}
+	// Write the history, e.g. to a file.
+	_ = recorder.WriteCSV(io.Discard)
+	// Output: Tick: 0, Entities: 1
+	// Tick: 10, Entities: 11
+	// Tick: 20, Entities: 21
+}
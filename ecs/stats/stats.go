@@ -1,10 +1,11 @@
-// Package stats provides the structs returned by ecs.World.Stats().
+// Package stats provides the structs returned by ecs.World.Stats(), and an [Exporter] for monitoring.
 package stats
 
 import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // World provide statistics for an [ecs.World].
@@ -15,6 +16,8 @@ type World struct {
 	ComponentCount int
 	// Component types, indexed by component ID.
 	ComponentTypes []reflect.Type
+	// Component statistics, indexed by component ID.
+	Components []Component
 	// Locked state of the world.
 	Locked bool
 	// Node statistics.
@@ -25,6 +28,9 @@ type World struct {
 	Memory int
 	// Number of cached filters.
 	CachedFilters int
+	// Query statistics, per filter, sorted by filter description.
+	// Only recorded with build tag `profile`, empty otherwise.
+	Queries []Query
 }
 
 // Entities provide statistics about [ecs.World] entities.
@@ -37,6 +43,42 @@ type Entities struct {
 	Recycled int
 	// Current capacity of the entities list.
 	Capacity int
+	// Entities with a name.
+	Named int
+}
+
+// Component provide statistics for a component type, aggregated over all archetypes.
+type Component struct {
+	// Component ID.
+	ID uint8
+	// Component type.
+	Type reflect.Type
+	// Size of a single component instance, in bytes.
+	ItemSize int
+	// Whether the component is a relation component.
+	IsRelation bool
+	// Number of entities that have the component.
+	Entities int
+	// Number of active archetypes that contain the component.
+	Archetypes int
+	// Total reserved memory for the component's columns, in bytes.
+	Memory int
+}
+
+// Query provide profiling statistics for queries with the same filter.
+//
+// Only recorded with build tag `profile`.
+type Query struct {
+	// Human-readable description of the filter.
+	Filter string
+	// Number of queries created.
+	Queries int
+	// Number of archetypes visited.
+	Archetypes int
+	// Number of entities iterated.
+	Entities int
+	// Total wall time between query creation and closing.
+	Time time.Duration
 }
 
 // Node provide statistics for an archetype graph node.
@@ -94,15 +136,37 @@ func (s *World) String() string {
 	fmt.Fprintf(&b, "  Components: %s\n", strings.Join(typeNames, ", "))
 	fmt.Fprint(&b, s.Entities.String())
 
+	for i := range s.Components {
+		fmt.Fprint(&b, s.Components[i].String())
+	}
+
 	for i := range s.Nodes {
 		fmt.Fprint(&b, s.Nodes[i].String())
 	}
 
+	for i := range s.Queries {
+		fmt.Fprint(&b, s.Queries[i].String())
+	}
+
 	return b.String()
 }
 
 func (s *Entities) String() string {
-	return fmt.Sprintf("Entities -- Used: %d, Recycled: %d, Total: %d, Capacity: %d\n", s.Used, s.Recycled, s.Total, s.Capacity)
+	return fmt.Sprintf("Entities -- Used: %d, Recycled: %d, Total: %d, Capacity: %d, Named: %d\n", s.Used, s.Recycled, s.Total, s.Capacity, s.Named)
+}
+
+func (s *Component) String() string {
+	return fmt.Sprintf(
+		"Component -- %s, Size: %d B, Relation: %t, Entities: %d, Archetypes: %d, Memory: %.1f kB\n",
+		s.Type.Name(), s.ItemSize, s.IsRelation, s.Entities, s.Archetypes, float64(s.Memory)/1024.0,
+	)
+}
+
+func (s *Query) String() string {
+	return fmt.Sprintf(
+		"Query -- %s, Queries: %d, Archetypes: %d, Entities: %d, Time: %s\n",
+		s.Filter, s.Queries, s.Archetypes, s.Entities, s.Time,
+	)
 }
 
 func (s *Node) String() string {
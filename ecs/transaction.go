@@ -0,0 +1,425 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/event"
+
+// Tx is a transaction on a [World], for speculative changes that can be rolled back.
+//
+// Create transactions with [World.Begin].
+// A transaction ends with either [Tx.Commit] or [Tx.Rollback].
+//
+// While a transaction is active, the world saves the state of each entity before it is changed for the first time
+// (copy-on-write per entity). This includes whether the entity is alive, its archetype and relation target,
+// and its component values. Rollback restores this state for all saved entities.
+//
+// Entity names are saved as a whole when they are changed for the first time,
+// by [World.SetName] or by removing a named entity.
+//
+// The state of an entity is saved when:
+//   - the entity is created or removed, including batch operations
+//   - components are added, removed or exchanged, including batch operations
+//   - the relation target is changed, including batch operations
+//   - a component is set with [World.Set] or [World.Assign],
+//     or with the generic [github.com/mlange-42/arche/generic.Map.Set] or [github.com/mlange-42/arche/generic.Map1.Assign] etc.
+//   - a [Query] created during the transaction enters the entity's archetype
+//   - the entity is saved explicitly with [Tx.Track] or [World.Track]
+//
+// ⚠️ Important: Component values modified through pointers obtained from [World.Get] or [World.GetUnchecked]
+// (and the generic [github.com/mlange-42/arche/generic.Map1] etc.) are not detected.
+// Call [Tx.Track] or [World.Track] for the entity before modifying its components that way.
+//
+// Listener events are deferred while a transaction is active.
+// They are delivered on commit of the outermost transaction, and dropped on rollback.
+// Events are delivered per entity, also for batch operations, even to listeners implementing [BatchListener].
+// Like with [github.com/mlange-42/arche/listener.Buffered], events reflect the state at the time of the event,
+// so their entities may already be dead or have other components when the events are delivered.
+//
+// Transactions can be nested. Only the innermost transaction can be committed or rolled back.
+// Committing a nested transaction merges its changes into the enclosing transaction,
+// and delivers its events only when the outermost transaction is committed.
+//
+// Limitations:
+//   - Resources are not part of transactions.
+//   - Rollback restores archetype membership, but not the order of entities within archetypes.
+//   - Archetypes, graph nodes and component IDs created during a transaction are retained on rollback.
+//   - [World.Reset] and [World.LoadEntities] are not possible during a transaction.
+//   - Entities can't be reserved with [World.Reserve] during a transaction, but they can be materialized.
+type Tx struct {
+	world         *World                   // The world of the transaction.
+	parent        *Tx                      // The enclosing transaction, if any.
+	rows          []txRow                  // Saved entity states, in order of saving.
+	indices       map[eid]int              // Mapping from entity IDs to indices in rows.
+	storage       map[*archNode]*archetype // Storage for saved component values, by archetype node.
+	events        int                      // Number of recorded events at the start of the transaction.
+	poolLen       int                      // Length of the entity pool at the start of the transaction.
+	poolNext      eid                      // Next recycled entity at the start of the transaction.
+	poolAvailable uint32                   // Number of recycled entities at the start of the transaction.
+	poolOpen      uint32                   // Number of reserved entities at the start of the transaction.
+	names         *entityNames             // Saved entity names. Nil if names were not changed.
+	done          bool                     // Whether the transaction was committed or rolled back.
+}
+
+// txRow is the saved state of an entity ID.
+type txRow struct {
+	pool     Entity    // Entry of the entity pool for the ID. Contains the generation of alive entities.
+	node     *archNode // Archetype node of the entity. Nil if the entity was not alive.
+	target   Entity    // Relation target of the entity.
+	index    uint32    // Row of the component values in the storage for the node.
+	id       eid       // Entity ID.
+	isTarget bool      // Whether the entity was marked as a potential relation target.
+}
+
+// txListener records events during transactions, for delivery on commit.
+type txListener struct {
+	listener Listener      // The world's effective listener.
+	events   []EntityEvent // Recorded events.
+}
+
+// Notify the listener. Records the event.
+func (l *txListener) Notify(world *World, evt EntityEvent) {
+	evt.AddedIDs = copyIDs(evt.AddedIDs)
+	evt.RemovedIDs = copyIDs(evt.RemovedIDs)
+	if evt.OldRelation != nil {
+		rel := *evt.OldRelation
+		evt.OldRelation = &rel
+	}
+	if evt.NewRelation != nil {
+		rel := *evt.NewRelation
+		evt.NewRelation = &rel
+	}
+	l.events = append(l.events, evt)
+}
+
+// Subscriptions of the listener.
+func (l *txListener) Subscriptions() event.Subscription {
+	return l.listener.Subscriptions()
+}
+
+// Components the listener subscribes to.
+func (l *txListener) Components() *Mask {
+	return l.listener.Components()
+}
+
+// Begin starts a [Tx] transaction.
+//
+// If a transaction is already active, the new transaction is nested into it.
+// See [Tx] for details.
+//
+// Panics when called on a locked world.
+func (w *World) Begin() *Tx {
+	w.checkLocked()
+	w.flushReserved()
+
+	pool := &w.entityPool
+	tx := &Tx{
+		world:         w,
+		parent:        w.tx,
+		indices:       map[eid]int{},
+		storage:       map[*archNode]*archetype{},
+		events:        len(w.txListener.events),
+		poolLen:       len(pool.entities),
+		poolNext:      pool.next,
+		poolAvailable: pool.available,
+		poolOpen:      pool.open,
+	}
+	w.tx = tx
+	if tx.parent == nil {
+		w.updateListener()
+	}
+	return tx
+}
+
+// Commit ends the transaction and keeps all changes.
+//
+// For the outermost transaction, delivers all deferred listener events.
+// For a nested transaction, changes and events are merged into the enclosing transaction.
+//
+// Panics when called on a locked world, if the transaction has already ended,
+// or if it is not the innermost transaction.
+func (t *Tx) Commit() {
+	t.checkActive()
+	w := t.world
+
+	if t.parent != nil {
+		t.parent.merge(t)
+		t.end()
+		return
+	}
+
+	t.end()
+	events := w.txListener.events
+	w.txListener.events = nil
+	w.updateListener()
+
+	for i := range events {
+		if w.listener == nil {
+			break
+		}
+		evt := &events[i]
+		trigger := w.listener.Subscriptions() & evt.EventTypes
+		if trigger != 0 && (subscribes(trigger, &evt.Added, &evt.Removed, w.listener.Components(), evt.OldRelation, evt.NewRelation) || w.listeners.watched(evt.Entity, evt.OldTarget, evt.NewTarget)) {
+			w.listener.Notify(w, *evt)
+		}
+	}
+}
+
+// Rollback ends the transaction and reverts all changes made since [World.Begin].
+//
+// Restores the saved entities, their archetypes, relation targets and component values,
+// entity names, and the state of the entity pool. Drops the listener events of the transaction.
+//
+// Panics when called on a locked world, if the transaction has already ended,
+// or if it is not the innermost transaction.
+func (t *Tx) Rollback() {
+	t.checkActive()
+	w := t.world
+	pool := &w.entityPool
+
+	// Remove the current rows of all saved entities.
+	cleanup := []*archetype{}
+	targets := []Entity{}
+	for i := range t.rows {
+		id := t.rows[i].id
+		if !t.isAlive(id) {
+			continue
+		}
+		if w.targetEntities.Get(id) {
+			targets = append(targets, pool.entities[id])
+		}
+		index := &w.entities[id]
+		arch := index.arch
+		w.removeRow(arch, index.index)
+		index.arch = nil
+		if arch.HasRelationComponent {
+			cleanup = append(cleanup, arch)
+		}
+	}
+
+	// Restore the entity pool.
+	if len(w.entities) > t.poolLen {
+		clear(w.entities[t.poolLen:])
+		w.entities = w.entities[:t.poolLen]
+	}
+	pool.entities = pool.entities[:t.poolLen]
+	pool.next = t.poolNext
+	pool.available = t.poolAvailable
+	pool.open = t.poolOpen
+
+	// Restore saved entities.
+	for i := range t.rows {
+		row := &t.rows[i]
+		if int(row.id) < t.poolLen {
+			pool.entities[row.id] = row.pool
+		}
+		if row.node == nil {
+			w.targetEntities.Set(row.id, false)
+			continue
+		}
+		if row.isTarget {
+			w.targetEntities.Set(row.id, true)
+		}
+		node := row.node
+		var arch *archetype
+		if node.HasRelation {
+			var ok bool
+			if arch, ok = node.GetArchetype(row.target); !ok {
+				arch = w.createArchetype(node, row.target, true)
+			}
+		} else {
+			arch = node.archetype
+		}
+		store := t.storage[node]
+		idx := arch.Alloc(row.pool)
+		for _, id := range node.Ids {
+			arch.SetPointer(idx, id, store.Get(row.index, id))
+		}
+		w.entities[row.id] = entityIndex{arch: arch, index: idx}
+	}
+
+	// Remove archetypes that are empty and have a dead target, and mark alive targets.
+	for _, arch := range cleanup {
+		target := arch.RelationTarget
+		if !arch.IsActive() || target.IsZero() {
+			continue
+		}
+		if t.isAliveEntity(target) {
+			w.targetEntities.Set(target.id, true)
+		} else if arch.Len() == 0 {
+			w.removeArchetype(arch)
+		}
+	}
+	for _, target := range targets {
+		if !t.isAliveEntity(target) {
+			w.cleanupArchetypes(target)
+		}
+	}
+
+	if t.names != nil {
+		w.names = *t.names
+	}
+
+	clear(w.txListener.events[t.events:])
+	w.txListener.events = w.txListener.events[:t.events]
+	t.end()
+	if t.parent == nil {
+		w.txListener.events = nil
+		w.updateListener()
+	}
+}
+
+// Track saves the state of an entity, including its component values, so that it is restored on rollback.
+//
+// Required before modifying components through pointers obtained from [World.Get] or [World.GetUnchecked].
+// Has no effect if the entity was already saved in this transaction.
+//
+// Panics if the entity is dead, if the transaction has already ended,
+// or if it is not the innermost transaction.
+func (t *Tx) Track(entity Entity) {
+	if t.done {
+		panic("transaction has already ended")
+	}
+	if t.world.tx != t {
+		panic("transaction is not the innermost transaction")
+	}
+	if !t.world.entityPool.Alive(entity) {
+		panic("can't track a dead entity")
+	}
+	t.capture(entity.id)
+}
+
+// Track saves the state of an entity in the innermost active transaction, if any. See [Tx.Track].
+//
+// Has no effect if no transaction is active.
+// Useful in code that modifies components through pointers, but has no access to the transaction.
+//
+// Panics if a transaction is active and the entity is dead.
+func (w *World) Track(entity Entity) {
+	if w.tx != nil {
+		w.tx.Track(entity)
+	}
+}
+
+// checkActive panics if the transaction can't be committed or rolled back.
+func (t *Tx) checkActive() {
+	if t.done {
+		panic("transaction has already ended")
+	}
+	if t.world.tx != t {
+		panic("transaction is not the innermost transaction")
+	}
+	t.world.checkLocked()
+}
+
+// end marks the transaction as ended, and makes the enclosing transaction the active one.
+func (t *Tx) end() {
+	t.done = true
+	t.world.tx = t.parent
+	t.rows = nil
+	t.indices = nil
+	t.storage = nil
+	t.names = nil
+}
+
+// isAliveEntity returns whether an entity is currently alive.
+// In contrast to [entityPool.Alive], also works for entities beyond the pool,
+// and for free IDs that already have the generation of the next entity created with them.
+func (t *Tx) isAliveEntity(entity Entity) bool {
+	return t.isAlive(entity.id) && t.world.entityPool.Alive(entity)
+}
+
+// isAlive returns whether an entity ID is currently alive.
+func (t *Tx) isAlive(id eid) bool {
+	pool := &t.world.entityPool
+	return int(id) < len(pool.entities) && pool.entities[id].id == id
+}
+
+// capture saves the state of an entity ID, if it was not already saved.
+func (t *Tx) capture(id eid) {
+	if _, ok := t.indices[id]; ok {
+		return
+	}
+	w := t.world
+	row := txRow{id: id}
+	if int(id) < t.poolLen {
+		row.pool = w.entityPool.entities[id]
+		row.isTarget = w.targetEntities.Get(id)
+	}
+	if t.isAlive(id) {
+		index := &w.entities[id]
+		row.node = index.arch.node
+		row.target = index.arch.RelationTarget
+		row.index = t.store(index.arch, index.index)
+	}
+	t.indices[id] = len(t.rows)
+	t.rows = append(t.rows, row)
+}
+
+// captureNext saves the state of the entity ID that will be used for the next created entity.
+func (t *Tx) captureNext() {
+	pool := &t.world.entityPool
+	if pool.available > 0 {
+		t.capture(pool.next)
+		return
+	}
+	t.capture(eid(len(pool.entities)))
+}
+
+// captureArchetype saves the state of the first count entities in an archetype.
+func (t *Tx) captureArchetype(arch *archetype, count uint32) {
+	var i uint32
+	for i = 0; i < count; i++ {
+		t.capture(arch.GetEntity(i).id)
+	}
+}
+
+// store copies the component values of an archetype row into the storage for its node.
+// Returns the row in the storage.
+func (t *Tx) store(arch *archetype, index uint32) uint32 {
+	node := arch.node
+	store, ok := t.storage[node]
+	if !ok {
+		store = &archetype{}
+		store.Init(node, &archetypeData{}, -1, false, uint8(len(arch.layouts)), Entity{})
+		t.storage[node] = store
+	}
+	idx := store.Alloc(arch.GetEntity(index))
+	for _, id := range node.Ids {
+		store.SetPointer(idx, id, arch.Get(index, id))
+	}
+	return idx
+}
+
+// captureNames saves the entity names, if not already saved.
+func (t *Tx) captureNames() {
+	if t.names != nil {
+		return
+	}
+	names := t.world.names.Clone()
+	t.names = &names
+}
+
+// merge adopts the saved states of a committed nested transaction,
+// for all entities that were not yet saved by this transaction.
+func (t *Tx) merge(nested *Tx) {
+	if t.names == nil {
+		t.names = nested.names
+	}
+	for i := range nested.rows {
+		row := nested.rows[i]
+		if _, ok := t.indices[row.id]; ok {
+			continue
+		}
+		if row.node != nil {
+			row.index = t.store(nested.storage[row.node], row.index)
+		}
+		t.indices[row.id] = len(t.rows)
+		t.rows = append(t.rows, row)
+	}
+}
+
+// copyIDs returns a copy of a slice of component IDs, or nil for an empty slice.
+func copyIDs(ids []ID) []ID {
+	if len(ids) == 0 {
+		return nil
+	}
+	return append([]ID{}, ids...)
+}
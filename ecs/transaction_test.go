@@ -0,0 +1,230 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// worldState returns a description of all alive entities, their components, values and relation targets.
+func worldState(w *World) map[Entity]string {
+	state := map[Entity]string{}
+	query := w.Query(All())
+	for query.Next() {
+		e := query.Entity()
+		desc := ""
+		for _, id := range query.Ids() {
+			tp, _ := w.registry.ComponentType(id.id)
+			value := reflect.NewAt(tp, query.Get(id)).Elem().Interface()
+			desc += fmt.Sprintf("%s%+v ", tp.Name(), value)
+		}
+		if rel := query.access.RelationComponent; query.access.HasRelationComponent {
+			desc += fmt.Sprintf("-> %v", query.Relation(rel))
+		}
+		state[e] = desc
+	}
+	return state
+}
+
+func TestTxRollback(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parents := []Entity{}
+	for i := 0; i < 5; i++ {
+		e := w.NewEntity(posID)
+		(*Position)(w.Get(e, posID)).X = i
+		parents = append(parents, e)
+	}
+	children := []Entity{}
+	for i := 0; i < 5; i++ {
+		e := NewBuilder(&w, posID, relID).WithRelation(relID).New(parents[i%2])
+		(*Position)(w.Get(e, posID)).X = 10 + i
+		children = append(children, e)
+	}
+	removed := w.NewEntity()
+	w.RemoveEntity(removed)
+
+	before := worldState(&w)
+	stats := w.Stats().Entities
+
+	tx := w.Begin()
+
+	w.RemoveEntity(parents[0])
+	w.RemoveEntity(children[4])
+	w.Add(parents[2], velID)
+	w.Remove(children[1], posID)
+	w.Relations().Set(children[2], relID, parents[3])
+	w.Set(parents[3], posID, &Position{X: 100})
+
+	newParent := w.NewEntity(posID)
+	w.Relations().Set(children[3], relID, newParent)
+	w.Batch().New(10, velID)
+	w.Batch().RemoveEntities(All(velID))
+
+	query := w.Query(All(posID))
+	for query.Next() {
+		(*Position)(query.Get(posID)).X += 1000
+	}
+
+	tx.Track(parents[4])
+	(*Position)(w.Get(parents[4], posID)).Y = 5
+
+	assert.NoError(t, w.Validate())
+	assert.NotEqual(t, before, worldState(&w))
+
+	tx.Rollback()
+
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, before, worldState(&w))
+	assert.Equal(t, stats, w.Stats().Entities)
+	assert.True(t, w.Alive(parents[0]))
+	assert.False(t, w.Alive(newParent))
+	assert.Equal(t, parents[0], w.Relations().Get(children[0], relID))
+
+	e := w.NewEntity()
+	assert.Equal(t, removed.id, e.id)
+	assert.Equal(t, removed.gen+1, e.gen)
+
+	assert.PanicsWithValue(t, "transaction has already ended", func() { tx.Rollback() })
+	assert.PanicsWithValue(t, "transaction has already ended", func() { tx.Commit() })
+}
+
+func TestTxCommit(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) { events = append(events, e) })
+	w.SetListener(&listener)
+
+	e1 := w.NewEntity(posID)
+	events = events[:0]
+
+	tx := w.Begin()
+	e2 := w.NewEntity(posID)
+	w.RemoveEntity(e1)
+	assert.Empty(t, events)
+
+	tx.Commit()
+	assert.NoError(t, w.Validate())
+	assert.False(t, w.Alive(e1))
+	assert.True(t, w.Alive(e2))
+
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, e2, events[0].Entity)
+	assert.Equal(t, []ID{posID}, events[0].AddedIDs)
+	assert.Equal(t, e1, events[1].Entity)
+	assert.Equal(t, []ID{posID}, events[1].RemovedIDs)
+
+	events = events[:0]
+	w.NewEntity()
+	assert.Equal(t, 1, len(events))
+
+	events = events[:0]
+	tx = w.Begin()
+	w.NewEntity()
+	tx.Rollback()
+	assert.Empty(t, events)
+
+	w.NewEntity()
+	assert.Equal(t, 1, len(events))
+}
+
+func TestTxNested(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	events := 0
+	listener := newTestListener(func(world *World, e EntityEvent) { events++ })
+	w.SetListener(&listener)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+	events = 0
+	before := worldState(&w)
+
+	outer := w.Begin()
+	w.Add(e1, velID)
+	middle := worldState(&w)
+
+	inner := w.Begin()
+	w.Add(e2, velID)
+	w.RemoveEntity(e1)
+
+	assert.PanicsWithValue(t, "transaction is not the innermost transaction", func() { outer.Commit() })
+
+	inner.Rollback()
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, middle, worldState(&w))
+
+	inner = w.Begin()
+	w.Add(e2, velID)
+	(*Position)(w.Get(e1, posID)).X = 0
+	w.Set(e1, posID, &Position{X: 5})
+	inner.Commit()
+	assert.Equal(t, 0, events)
+
+	outer.Rollback()
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, before, worldState(&w))
+	assert.Equal(t, 0, events)
+
+	outer = w.Begin()
+	w.Add(e1, velID)
+	inner = w.Begin()
+	w.Add(e2, velID)
+	inner.Commit()
+	outer.Commit()
+	assert.Equal(t, 2, events)
+}
+
+func TestTxPanics(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	e := w.NewEntity(posID)
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Begin() })
+	query.Close()
+
+	tx := w.Begin()
+	assert.PanicsWithValue(t, "can't reset the world during a transaction", func() { w.Reset() })
+	assert.PanicsWithValue(t, "can't load entities during a transaction", func() { w.LoadEntities(&EntityDump{}) })
+
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { tx.Rollback() })
+	query.Close()
+
+	w.RemoveEntity(e)
+	assert.PanicsWithValue(t, "can't track a dead entity", func() { tx.Track(e) })
+	tx.Commit()
+
+	assert.PanicsWithValue(t, "transaction has already ended", func() { tx.Track(e) })
+}
+
+func TestWorldTrack(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	e := w.NewEntity(posID)
+
+	w.Track(e)
+	(*Position)(w.Get(e, posID)).X = 1
+
+	tx := w.Begin()
+	w.Track(e)
+	(*Position)(w.Get(e, posID)).X = 2
+	tx.Rollback()
+	assert.Equal(t, 1, (*Position)(w.Get(e, posID)).X)
+
+	w.RemoveEntity(e)
+	w.Track(e)
+	tx = w.Begin()
+	assert.PanicsWithValue(t, "can't track a dead entity", func() { w.Track(e) })
+	tx.Commit()
+}
@@ -49,8 +49,9 @@ type CompInfo struct {
 //
 // See [World.DumpEntities] and [World.LoadEntities].
 type EntityDump struct {
-	Entities  []Entity // Entities in the World's entity pool.
-	Alive     []uint32 // IDs of all alive entities in query iteration order.
-	Next      uint32   // The next free entity of the World's entity pool.
-	Available uint32   // The number of allocated and available entities in the World's entity pool.
+	Entities  []Entity          // Entities in the World's entity pool.
+	Alive     []uint32          // IDs of all alive entities in query iteration order.
+	Next      uint32            // The next free entity of the World's entity pool.
+	Available uint32            // The number of allocated and available entities in the World's entity pool.
+	Names     map[uint32]string // Names of named entities, by entity ID. See [World.SetName].
 }
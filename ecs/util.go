@@ -3,6 +3,7 @@ package ecs
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/mlange-42/arche/ecs/event"
 )
@@ -89,12 +90,14 @@ func subscribes(trigger event.Subscription, added *Mask, removed *Mask, subs *Ma
 	return false
 }
 
-// Manages locks by mask bits.
+// Manages locks by mask bits, and read-only locks by an atomic counter.
 //
 // The number of simultaneous locks at a given time is limited to [MaskTotalBits].
+// The number of read-only locks is not limited.
 type lockMask struct {
 	locks   Mask    // The actual locks.
 	bitPool bitPool // The bit pool for getting and recycling bits.
+	readers int32   // The number of read-only locks. Only accessed atomically.
 }
 
 // Lock the world and get the Lock bit for later unlocking.
@@ -113,15 +116,31 @@ func (m *lockMask) Unlock(l uint8) {
 	m.bitPool.Recycle(l)
 }
 
-// IsLocked returns whether the world is locked by any queries.
+// LockRead acquires a read-only lock. Safe for concurrent use.
+func (m *lockMask) LockRead() {
+	atomic.AddInt32(&m.readers, 1)
+}
+
+// UnlockRead releases a read-only lock. Safe for concurrent use.
+func (m *lockMask) UnlockRead() {
+	atomic.AddInt32(&m.readers, -1)
+}
+
+// IsReadLocked returns whether the world is locked by any read-only queries.
+func (m *lockMask) IsReadLocked() bool {
+	return atomic.LoadInt32(&m.readers) > 0
+}
+
+// IsLocked returns whether the world is locked by any queries, including read-only queries.
 func (m *lockMask) IsLocked() bool {
-	return !m.locks.IsZero()
+	return !m.locks.IsZero() || m.IsReadLocked()
 }
 
 // Reset the locks and the pool.
 func (m *lockMask) Reset() {
 	m.locks = Mask{}
 	m.bitPool.Reset()
+	atomic.StoreInt32(&m.readers, 0)
 }
 
 // pagedSlice is a paged collection working with pages of length 32 slices.
@@ -0,0 +1,359 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Validate checks the internal state of the world for consistency.
+//
+// Returns nil if the world is consistent.
+// Otherwise, returns an error that joins all found violations, one per line.
+// Individual violations can be obtained by unwrapping it via interface{ Unwrap() []error }.
+//
+// Checks the entity pool, the mapping from entities to archetype rows, entity names,
+// archetype and node masks, relation archetypes and relation targets,
+// the filter cache and the world's locks.
+// In deterministic mode, also checks the order of nodes and archetypes.
+//
+// Validation iterates all entities, archetypes and cached filters,
+// and is intended for debugging and testing rather than for regular use.
+func (w *World) Validate() error {
+	v := validator{world: w}
+	alive := v.validateEntityPool()
+	v.validateEntities(alive)
+	v.validateNames(alive)
+	v.validateArchetypes()
+	v.validateCache()
+	v.validateLocks()
+	if w.config.deterministic {
+		v.validateOrder()
+	}
+	return errors.Join(v.errors...)
+}
+
+// validator collects violations found by [World.Validate].
+type validator struct {
+	world  *World
+	errors []error
+}
+
+// addf adds a violation.
+func (v *validator) addf(format string, args ...any) {
+	v.errors = append(v.errors, fmt.Errorf(format, args...))
+}
+
+// label returns the component type names of a node, for violation messages.
+func (v *validator) label(node *archNode) string {
+	names := make([]string, len(node.Ids))
+	for i, id := range node.Ids {
+		names[i] = v.world.registry.Types[id.id].Name()
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// validateEntityPool checks the implicit linked list of recycled entities.
+// Returns whether each entity ID is alive. Reserved entities are not alive.
+func (v *validator) validateEntityPool() []bool {
+	pool := &v.world.entityPool
+	alive := make([]bool, len(pool.entities))
+	for i := 1; i < len(alive); i++ {
+		alive[i] = true
+	}
+
+	next := pool.next
+	for i := uint32(0); i < pool.available; i++ {
+		if next == 0 || int(next) >= len(pool.entities) {
+			v.addf("entity pool: recycled entity %d of %d has invalid ID %d", i, pool.available, next)
+			break
+		}
+		if !alive[next] {
+			v.addf("entity pool: recycled entity %d is listed more than once", next)
+			break
+		}
+		alive[next] = false
+		next = pool.entities[next].id
+	}
+
+	for _, id := range pool.reserved {
+		if int(id) < len(alive) {
+			alive[id] = false
+		}
+	}
+	for i := 1; i < len(alive); i++ {
+		if pool.entities[i].id == reservedID {
+			alive[i] = false
+		}
+	}
+
+	for i := 1; i < len(alive); i++ {
+		if alive[i] && pool.entities[i].id != eid(i) {
+			v.addf("entity pool: alive entity at index %d has ID %d", i, pool.entities[i].id)
+		}
+	}
+	return alive
+}
+
+// validateEntities checks the mapping from alive entities to archetype rows.
+func (v *validator) validateEntities(alive []bool) {
+	w := v.world
+	if len(w.entities) < len(alive) {
+		v.addf("entities: index has length %d, but the entity pool has %d entries", len(w.entities), len(alive))
+		return
+	}
+	for i := 1; i < len(alive); i++ {
+		if !alive[i] {
+			continue
+		}
+		entity := w.entityPool.entities[i]
+		index := &w.entities[i]
+		if index.arch == nil {
+			v.addf("entities: alive entity %v has no archetype", entity)
+			continue
+		}
+		if index.index >= index.arch.len {
+			v.addf("entities: alive entity %v has row %d, but its archetype has only %d rows", entity, index.index, index.arch.len)
+			continue
+		}
+		if e := index.arch.GetEntity(index.index); e != entity {
+			v.addf("entities: alive entity %v points to row %d of its archetype, which holds entity %v", entity, index.index, e)
+		}
+	}
+}
+
+// validateNames checks that names refer to alive entities, in both directions.
+func (v *validator) validateNames(alive []bool) {
+	names := &v.world.names
+	for id, name := range names.byEntity {
+		if int(id) >= len(alive) || !alive[id] {
+			v.addf("names: name '%s' belongs to dead entity ID %d", name, id)
+			continue
+		}
+		entity := v.world.entityPool.entities[id]
+		if other, ok := names.byName[name]; !ok || other != entity {
+			v.addf("names: name '%s' of entity %v maps to entity %v", name, entity, other)
+		}
+	}
+	if len(names.byName) != len(names.byEntity) {
+		v.addf("names: %d names map to %d entities", len(names.byName), len(names.byEntity))
+	}
+}
+
+// validateArchetypes checks archetype rows, archetype masks and relation archetypes.
+func (v *validator) validateArchetypes() {
+	w := v.world
+	rows := 0
+	nodes := w.nodes.Len()
+	var i int32
+	for i = 0; i < nodes; i++ {
+		node := w.nodes.Get(i)
+		if !node.IsActive {
+			continue
+		}
+		v.validateNode(node)
+
+		arches := node.Archetypes()
+		archLen := arches.Len()
+		var j int32
+		for j = 0; j < archLen; j++ {
+			arch := arches.Get(j)
+			if !arch.IsActive() {
+				continue
+			}
+			rows += int(arch.len)
+			v.validateArchetype(node, arch)
+		}
+	}
+	if used := w.entityPool.Len(); rows != used {
+		v.addf("archetypes: %d rows in total, but %d alive entities", rows, used)
+	}
+}
+
+// validateNode checks the mask and the relation archetypes of a node.
+func (v *validator) validateNode(node *archNode) {
+	w := v.world
+	mask := All(node.Ids...)
+	if mask != node.Mask {
+		v.addf("node %s: mask does not match the component IDs", v.label(node))
+	}
+	if !node.HasRelation {
+		if node.archetypeMap != nil {
+			v.addf("node %s: node without relation has a relation archetype map", v.label(node))
+		}
+		return
+	}
+	if !node.Mask.Get(node.Relation) {
+		v.addf("node %s: relation component %d is not in the node's mask", v.label(node), node.Relation.id)
+	}
+	for target, arch := range node.archetypeMap {
+		if arch.RelationTarget != target {
+			v.addf("node %s: archetype for target %v has relation target %v", v.label(node), target, arch.RelationTarget)
+		}
+		if !arch.IsActive() {
+			v.addf("node %s: archetype for target %v is inactive", v.label(node), target)
+		}
+		if target.IsZero() {
+			continue
+		}
+		if w.entityPool.Alive(target) {
+			if !w.isTarget(target) {
+				v.addf("node %s: alive relation target %v is not marked as target entity", v.label(node), target)
+			}
+		} else if arch.len == 0 {
+			v.addf("node %s: empty archetype for dead relation target %v was not removed", v.label(node), target)
+		}
+	}
+}
+
+// validateArchetype checks the mask and the rows of an active archetype.
+func (v *validator) validateArchetype(node *archNode, arch *archetype) {
+	w := v.world
+	if arch.node != node {
+		v.addf("archetype %s: archetype does not point to its node", v.label(node))
+	}
+	if arch.Mask != node.Mask {
+		v.addf("archetype %s: mask does not match the node's mask", v.label(node))
+	}
+	if arch.HasRelationComponent != node.HasRelation {
+		v.addf("archetype %s: relation flag does not match the node's relation flag", v.label(node))
+	}
+	if node.HasRelation {
+		if arch.RelationComponent != node.Relation {
+			v.addf("archetype %s: relation component %d does not match the node's relation %d", v.label(node), arch.RelationComponent.id, node.Relation.id)
+		}
+		if a, ok := node.archetypeMap[arch.RelationTarget]; !ok || a != arch {
+			v.addf("archetype %s: archetype for target %v is not registered in its node", v.label(node), arch.RelationTarget)
+		}
+	}
+	if arch.len > arch.cap {
+		v.addf("archetype %s: %d rows exceed capacity %d", v.label(node), arch.len, arch.cap)
+	}
+
+	var j uint32
+	for j = 0; j < arch.len; j++ {
+		entity := arch.GetEntity(j)
+		if int(entity.id) >= len(w.entities) || !w.entityPool.Alive(entity) {
+			v.addf("archetype %s: row %d holds dead entity %v", v.label(node), j, entity)
+			continue
+		}
+		index := &w.entities[entity.id]
+		if index.arch != arch || index.index != j {
+			v.addf("archetype %s: row %d holds entity %v, which points to another row or archetype", v.label(node), j, entity)
+		}
+	}
+}
+
+// validateCache checks that cache entries contain exactly the matching archetypes.
+func (v *validator) validateCache() {
+	w := v.world
+	for i := range w.filterCache.filters {
+		e := &w.filterCache.filters[i]
+		if idx, ok := w.filterCache.indices[e.ID]; !ok || idx != i {
+			v.addf("cache: filter %d is not indexed correctly", e.ID)
+		}
+
+		expected := map[*archetype]bool{}
+		for _, arch := range w.getArchetypes(e.Filter) {
+			expected[arch] = true
+		}
+		found := map[*archetype]bool{}
+		for j, arch := range e.Archetypes.pointers {
+			if found[arch] {
+				v.addf("cache: filter %d contains archetype %s more than once", e.ID, v.label(arch.node))
+				continue
+			}
+			found[arch] = true
+			if !expected[arch] {
+				v.addf("cache: filter %d contains archetype %s that does not match or is inactive", e.ID, v.label(arch.node))
+			}
+			if e.Indices != nil && arch.HasRelation() {
+				if idx, ok := e.Indices[arch]; !ok || idx != j {
+					v.addf("cache: filter %d has a wrong index for archetype %s", e.ID, v.label(arch.node))
+				}
+			}
+		}
+		for arch := range expected {
+			if !found[arch] {
+				v.addf("cache: filter %d is missing matching archetype %s", e.ID, v.label(arch.node))
+			}
+		}
+	}
+	if len(w.filterCache.indices) != len(w.filterCache.filters) {
+		v.addf("cache: %d filter indices, but %d filters", len(w.filterCache.indices), len(w.filterCache.filters))
+	}
+}
+
+// validateOrder checks that nodes, relation archetypes and cache entries are in deterministic order.
+func (v *validator) validateOrder() {
+	w := v.world
+	for i := 1; i < len(w.nodePointers); i++ {
+		if compareIDs(w.nodePointers[i-1].Ids, w.nodePointers[i].Ids) >= 0 {
+			v.addf("order: node %s is not sorted before node %s", v.label(w.nodePointers[i-1]), v.label(w.nodePointers[i]))
+		}
+	}
+	for _, node := range w.nodePointers {
+		if !node.deterministic {
+			v.addf("order: node %s is not in deterministic mode", v.label(node))
+		}
+		if !node.IsActive || !node.HasRelation {
+			continue
+		}
+		if len(node.ordered.pointers) != len(node.archetypeMap) {
+			v.addf("order: node %s has %d ordered archetypes, but %d active archetypes", v.label(node), len(node.ordered.pointers), len(node.archetypeMap))
+		}
+		v.validateSorted("node "+v.label(node), node.ordered.pointers)
+	}
+	for i := range w.filterCache.filters {
+		e := &w.filterCache.filters[i]
+		v.validateSorted(fmt.Sprintf("cache: filter %d", e.ID), e.Archetypes.pointers)
+	}
+}
+
+// validateSorted checks that archetypes are in deterministic order.
+func (v *validator) validateSorted(label string, arches []*archetype) {
+	for i := 1; i < len(arches); i++ {
+		if compareArchetypes(arches[i-1], arches[i]) >= 0 {
+			v.addf("order: %s: archetype %s with target %v is not sorted before archetype %s with target %v",
+				label, v.label(arches[i-1].node), arches[i-1].RelationTarget, v.label(arches[i].node), arches[i].RelationTarget)
+		}
+	}
+}
+
+// validateLocks checks the world's lock bits against the lock bit pool.
+func (v *validator) validateLocks() {
+	locks := &v.world.locks
+	pool := &locks.bitPool
+
+	free := Mask{}
+	next := pool.next
+	for i := uint8(0); i < pool.available; i++ {
+		if uint16(next) >= pool.length || free.Get(id(next)) {
+			v.addf("locks: invalid list of recycled lock bits")
+			return
+		}
+		free.Set(id(next), true)
+		next = pool.bits[next]
+	}
+
+	if readers := atomic.LoadInt32(&locks.readers); readers < 0 {
+		v.addf("locks: negative number of read-only locks %d", readers)
+	}
+
+	used := int(pool.length) - int(pool.available)
+	if set := locks.locks.TotalBitsSet(); set != used {
+		v.addf("locks: %d lock bits set, but %d bits in use", set, used)
+	}
+	if both := locks.locks.And(&free); !both.IsZero() {
+		v.addf("locks: recycled lock bits are still set")
+	}
+}
+
+// isTarget returns whether an entity is marked as a potential relation target.
+func (w *World) isTarget(entity Entity) bool {
+	if int(entity.id) >= len(w.targetEntities.data)*wordSize {
+		return false
+	}
+	return w.targetEntities.Get(entity.id)
+}
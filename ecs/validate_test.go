@@ -0,0 +1,103 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldValidate(t *testing.T) {
+	w := NewWorld(8)
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	assert.NoError(t, w.Validate())
+
+	f1 := All(posID)
+	c1 := w.Cache().Register(&f1)
+	f2 := All(relID)
+	c2 := w.Cache().Register(&f2)
+
+	parents := []Entity{}
+	for i := 0; i < 10; i++ {
+		parents = append(parents, w.NewEntity(posID))
+	}
+	assert.NoError(t, w.Validate())
+
+	for _, parent := range parents {
+		NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(20, parent)
+	}
+	w.Batch().New(50, posID, velID)
+	assert.NoError(t, w.Validate())
+
+	rel := NewRelationFilter(All(relID), parents[3])
+	w.Batch().RemoveEntities(&rel)
+	w.RemoveEntity(parents[3])
+	w.RemoveEntity(parents[4])
+	assert.NoError(t, w.Validate())
+
+	w.Batch().Remove(All(posID, velID), velID)
+	w.Relations().SetBatch(All(relID), relID, parents[5])
+	assert.NoError(t, w.Validate())
+
+	query := w.Query(&c1)
+	assert.NoError(t, w.Validate())
+	query.Close()
+
+	w.Cache().Unregister(&c1)
+	w.Cache().Unregister(&c2)
+	w.Reset()
+	assert.NoError(t, w.Validate())
+}
+
+func TestWorldValidateViolations(t *testing.T) {
+	newWorld := func() (World, Entity, Entity) {
+		w := NewWorld(8)
+		posID := ComponentID[Position](&w)
+		relID := ComponentID[testRelationA](&w)
+		filter := All(posID)
+		w.Cache().Register(&filter)
+
+		parent := w.NewEntity(posID)
+		child := w.NewEntity(posID, relID)
+		w.Relations().Set(child, relID, parent)
+		w.RemoveEntity(w.NewEntity())
+		assert.NoError(t, w.Validate())
+		return w, parent, child
+	}
+
+	w, parent, _ := newWorld()
+	w.entityPool.entities[parent.id].id = 5
+	assert.ErrorContains(t, w.Validate(), "entity pool: alive entity at index 1 has ID 5")
+
+	w, parent, child := newWorld()
+	w.entities[parent.id].index = 1
+	err := w.Validate()
+	assert.ErrorContains(t, err, "entities: alive entity {1 0} has row 1, but its archetype has only 1 rows")
+	assert.ErrorContains(t, err, "archetype [Position]: row 0 holds entity {1 0}, which points to another row or archetype")
+
+	w, parent, child = newWorld()
+	w.entities[parent.id].arch = w.entities[child.id].arch
+	assert.ErrorContains(t, w.Validate(), "entities: alive entity {1 0} points to row 0 of its archetype, which holds entity {2 0}")
+
+	w, parent, _ = newWorld()
+	w.targetEntities.Set(parent.id, false)
+	assert.ErrorContains(t, w.Validate(), "node [Position, testRelationA]: alive relation target {1 0} is not marked as target entity")
+
+	w, _, child = newWorld()
+	w.entities[child.id].arch.Mask = All()
+	assert.ErrorContains(t, w.Validate(), "archetype [Position, testRelationA]: mask does not match the node's mask")
+
+	w, _, _ = newWorld()
+	w.filterCache.filters[0].Archetypes.pointers = nil
+	assert.ErrorContains(t, w.Validate(), "cache: filter 0 is missing matching archetype [Position]")
+
+	w, _, _ = newWorld()
+	w.locks.locks.Set(id(3), true)
+	assert.ErrorContains(t, w.Validate(), "locks: 1 lock bits set, but 0 bits in use")
+
+	w, _, _ = newWorld()
+	w.entityPool.available = 2
+	assert.ErrorContains(t, w.Validate(), "entity pool: recycled entity 1 of 2 has invalid ID 0")
+}
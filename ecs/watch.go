@@ -0,0 +1,192 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/event"
+
+// WatchHandle identifies a watch subscription created with [World.Watch] or [World.WatchTarget].
+// Use it to unsubscribe with [World.Unwatch].
+type WatchHandle struct {
+	entity Entity // The watched entity or relation target.
+	id     uint32 // Unique ID of the subscription.
+	target bool   // Whether the subscription is for a relation target.
+}
+
+// watch is a single per-entity subscription.
+type watch struct {
+	callback func(*World, EntityEvent) // The callback to notify.
+	id       uint32                    // Unique ID of the subscription.
+	subs     event.Subscription        // Subscribed event types.
+}
+
+// notifyWatches notifies per-entity and relation target watches.
+func (l *worldListener) notifyWatches(world *World, evt *EntityEvent) {
+	if watches, ok := l.entities[evt.Entity]; ok {
+		for i := range watches {
+			if watches[i].subs&evt.EventTypes != 0 {
+				watches[i].callback(world, *evt)
+			}
+		}
+	}
+
+	if len(l.targets) > 0 {
+		if !evt.OldTarget.IsZero() {
+			l.notifyTarget(world, evt.OldTarget, evt)
+		}
+		if !evt.NewTarget.IsZero() && evt.NewTarget != evt.OldTarget {
+			l.notifyTarget(world, evt.NewTarget, evt)
+		}
+	}
+
+	if evt.Contains(event.EntityRemoved) {
+		l.removeWatches(evt.Entity)
+	}
+}
+
+// watched returns whether there are watches for an entity, or for its old or new relation target.
+// Includes target watches of the entity itself, as they are removed together with the entity.
+//
+// Used together with the component pre-filtering of listeners, see [worldListener.Components].
+func (l *worldListener) watched(entity, oldTarget, newTarget Entity) bool {
+	if l.watchCount == 0 {
+		return false
+	}
+	if _, ok := l.entities[entity]; ok {
+		return true
+	}
+	return l.watchedTarget(entity) || l.watchedTarget(oldTarget) || l.watchedTarget(newTarget)
+}
+
+// watchedRange returns whether there may be watches for a range of entities with the given old and new relation target.
+func (l *worldListener) watchedRange(oldTarget, newTarget Entity) bool {
+	if l.watchCount == 0 {
+		return false
+	}
+	return len(l.entities) > 0 || len(l.targets) > 0 && (l.watchedTarget(oldTarget) || l.watchedTarget(newTarget))
+}
+
+// watchedTarget returns whether there are watches for a relation target.
+func (l *worldListener) watchedTarget(target Entity) bool {
+	if len(l.targets) == 0 {
+		return false
+	}
+	_, ok := l.targets[target]
+	return ok
+}
+
+// notifyTarget notifies the watches of a relation target.
+func (l *worldListener) notifyTarget(world *World, target Entity, evt *EntityEvent) {
+	if watches, ok := l.targets[target]; ok {
+		for i := range watches {
+			if watches[i].subs&evt.EventTypes != 0 {
+				watches[i].callback(world, *evt)
+			}
+		}
+	}
+}
+
+// AddWatch adds a watch for an entity or relation target.
+func (l *worldListener) AddWatch(entity Entity, target bool, subs event.Subscription, callback func(*World, EntityEvent)) WatchHandle {
+	w := watch{callback: callback, id: l.nextWatch, subs: subs}
+	l.nextWatch++
+	if target {
+		l.targets[entity] = append(l.targets[entity], w)
+	} else {
+		l.entities[entity] = append(l.entities[entity], w)
+	}
+	l.watchEvents |= subs
+	l.watchCount++
+	return WatchHandle{entity: entity, id: w.id, target: target}
+}
+
+// RemoveWatch removes a watch. Returns whether it was found.
+func (l *worldListener) RemoveWatch(h WatchHandle) bool {
+	watches := l.entities
+	if h.target {
+		watches = l.targets
+	}
+	list := watches[h.entity]
+	for i := range list {
+		if list[i].id != h.id {
+			continue
+		}
+		if len(list) == 1 {
+			delete(watches, h.entity)
+		} else {
+			// Copy, as the list may currently be iterated by Notify.
+			newList := make([]watch, 0, len(list)-1)
+			newList = append(newList, list[:i]...)
+			watches[h.entity] = append(newList, list[i+1:]...)
+		}
+		l.watchCount--
+		if l.watchCount == 0 {
+			l.watchEvents = 0
+		}
+		return true
+	}
+	return false
+}
+
+// removeWatches removes all watches of a removed entity.
+func (l *worldListener) removeWatches(entity Entity) {
+	if list, ok := l.entities[entity]; ok {
+		l.watchCount -= len(list)
+		delete(l.entities, entity)
+	}
+	if list, ok := l.targets[entity]; ok {
+		l.watchCount -= len(list)
+		delete(l.targets, entity)
+	}
+}
+
+// ResetWatches removes all watches.
+func (l *worldListener) ResetWatches() {
+	l.entities = map[Entity][]watch{}
+	l.targets = map[Entity][]watch{}
+	l.watchCount = 0
+	l.watchEvents = 0
+}
+
+// Watch subscribes a callback to events of a single entity.
+// The callback is called for all events of the entity that are contained in the subscription mask.
+//
+// The watch is removed automatically when the entity is removed, after notifying about the removal.
+// Returns a handle that can be used to remove the watch with [World.Unwatch] before.
+//
+// Watches are notified after all listeners (see [World.SetListener] and [World.AddListener]).
+// Similar to listeners, the world is locked during entity removal events.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (w *World) Watch(entity Entity, subs event.Subscription, callback func(*World, EntityEvent)) WatchHandle {
+	if !w.entityPool.Alive(entity) {
+		panic("can't watch a dead entity")
+	}
+	h := w.listeners.AddWatch(entity, false, subs, callback)
+	w.updateListener()
+	return h
+}
+
+// WatchTarget subscribes a callback to events of all entities that have the given entity as relation target.
+// The callback is called for all events of these entities that are contained in the subscription mask,
+// including entities that change their target to or from the given entity.
+//
+// The watch is removed automatically when the target entity is removed.
+// Returns a handle that can be used to remove the watch with [World.Unwatch] before.
+//
+// See also [World.Watch].
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (w *World) WatchTarget(target Entity, subs event.Subscription, callback func(*World, EntityEvent)) WatchHandle {
+	if !w.entityPool.Alive(target) {
+		panic("can't watch a dead relation target")
+	}
+	h := w.listeners.AddWatch(target, true, subs, callback)
+	w.updateListener()
+	return h
+}
+
+// Unwatch removes a watch subscription created with [World.Watch] or [World.WatchTarget].
+// Does nothing if the subscription was already removed.
+func (w *World) Unwatch(handle WatchHandle) {
+	if w.listeners.RemoveWatch(handle) {
+		w.updateListener()
+	}
+}
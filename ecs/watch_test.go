@@ -0,0 +1,167 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldWatch(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+
+	events := []EntityEvent{}
+	allEvents := []EntityEvent{}
+	ls := newTestListener(func(world *World, e EntityEvent) {
+		allEvents = append(allEvents, e)
+	})
+	w.SetListener(&ls)
+
+	h := w.Watch(e1, event.ComponentAdded|event.EntityRemoved, func(world *World, e EntityEvent) {
+		assert.Equal(t, e1, e.Entity)
+		events = append(events, e)
+	})
+
+	w.Add(e1, posID)
+	w.Add(e2, posID)
+	w.Remove(e1, posID)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, 3, len(allEvents))
+
+	w.Unwatch(h)
+	w.Unwatch(h)
+	assert.Equal(t, 0, w.listeners.watchCount)
+	assert.Equal(t, &ls, w.listener)
+	w.Add(e1, velID)
+	assert.Equal(t, 1, len(events))
+
+	w.Watch(e1, event.All, func(world *World, e EntityEvent) {
+		events = append(events, e)
+	})
+	w.Watch(e1, event.ComponentRemoved, func(world *World, e EntityEvent) {
+		events = append(events, e)
+	})
+	w.SetListener(nil)
+	assert.Equal(t, &w.listeners, w.listener)
+	w.RemoveEntity(e1)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, 4, len(allEvents))
+	assert.Equal(t, 0, w.listeners.watchCount)
+	assert.Equal(t, 0, len(w.listeners.entities))
+
+	e3 := w.NewEntity()
+	assert.Equal(t, e1.id, e3.id)
+	w.Add(e3, posID)
+	assert.Equal(t, 3, len(events))
+
+	assert.Panics(t, func() { w.Watch(e1, event.All, nil) })
+
+	w.Watch(e3, event.All, nil)
+	w.SetListener(&ls)
+	w.Reset()
+	assert.Equal(t, 0, w.listeners.watchCount)
+	assert.Equal(t, &ls, w.listener)
+}
+
+func TestWorldWatchUnwatchInCallback(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e := w.NewEntity()
+
+	count := 0
+	var h1 WatchHandle
+	h1 = w.Watch(e, event.ComponentAdded, func(world *World, evt EntityEvent) {
+		count++
+		world.Unwatch(h1)
+	})
+	w.Watch(e, event.ComponentAdded, func(world *World, evt EntityEvent) {
+		count++
+	})
+
+	w.Add(e, posID)
+	assert.Equal(t, 2, count)
+	w.Remove(e, posID)
+	w.Add(e, posID)
+	assert.Equal(t, 3, count)
+}
+
+func TestWorldWatchTarget(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[ChildOf](&w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	events := []EntityEvent{}
+	w.WatchTarget(parent1, event.All, func(world *World, e EntityEvent) {
+		events = append(events, e)
+	})
+
+	child := NewBuilder(&w, relID).WithRelation(relID).New(parent1)
+	assert.Equal(t, 1, len(events))
+
+	w.Add(child, posID)
+	assert.Equal(t, 2, len(events))
+
+	w.Relations().Set(child, relID, parent2)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, parent1, events[2].OldTarget)
+
+	w.Remove(child, posID)
+	assert.Equal(t, 3, len(events))
+
+	w.Relations().Set(child, relID, parent1)
+	assert.Equal(t, 4, len(events))
+
+	w.RemoveEntity(parent1)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, 0, len(w.listeners.targets))
+
+	w.RemoveEntity(child)
+	assert.Equal(t, 4, len(events))
+
+	assert.Panics(t, func() { w.WatchTarget(parent1, event.All, nil) })
+}
+
+func TestWorldWatchTargetTx(t *testing.T) {
+	w := NewWorld()
+	relID := ComponentID[ChildOf](&w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	events1 := []EntityEvent{}
+	w.WatchTarget(parent1, event.All, func(world *World, e EntityEvent) {
+		events1 = append(events1, e)
+	})
+	events2 := []EntityEvent{}
+	w.WatchTarget(parent2, event.All, func(world *World, e EntityEvent) {
+		events2 = append(events2, e)
+	})
+
+	tx := w.Begin()
+	child1 := NewBuilder(&w, relID).WithRelation(relID).New(parent1)
+	w.RemoveEntity(child1)
+	child2 := NewBuilder(&w, relID).WithRelation(relID).New(parent2)
+	assert.Equal(t, child1.id, child2.id)
+	assert.Empty(t, events1)
+	tx.Commit()
+
+	assert.Equal(t, 2, len(events1))
+	assert.Equal(t, child1, events1[0].Entity)
+	assert.Equal(t, parent1, events1[0].NewTarget)
+	assert.Equal(t, child1, events1[1].Entity)
+	assert.Equal(t, parent1, events1[1].OldTarget)
+	assert.True(t, events1[1].NewTarget.IsZero())
+
+	assert.Equal(t, 1, len(events2))
+	assert.Equal(t, child2, events2[0].Entity)
+	assert.Equal(t, parent2, events2[0].NewTarget)
+}
@@ -15,7 +15,8 @@ import (
 // For more advanced functionality, see [World.Relations], [World.Resources],
 // [World.Batch], [World.Cache] and [Builder].
 type World struct {
-	listener       Listener                  // EntityEvent listener.
+	listener       Listener                  // Effective EntityEvent listener. Either a single listener, or listeners.
+	listeners      worldListener             // Registered listeners and per-entity watches.
 	nodePointers   []*archNode               // Helper list of all node pointers for queries.
 	entities       []entityIndex             // Mapping from entities to archetype and index.
 	targetEntities bitSet                    // Whether entities are potential relation targets. Used for archetype cleanup.
@@ -26,10 +27,16 @@ type World struct {
 	nodeData       pagedSlice[nodeData]      // The archetype graph's data.
 	archetypes     pagedSlice[archetype]     // Archetypes that have no relations components.
 	entityPool     entityPool                // Pool for entities.
+	names          entityNames               // Unique entity names.
 	stats          stats.World               // Cached world statistics.
+	profiler       *queryProfiler            // Query profiler. Only used with build tag `profile`.
+	tx             *Tx                       // Innermost active transaction, if any.
+	txListener     txListener                // Records events during transactions.
 	resources      Resources                 // World resources.
 	registry       componentRegistry         // Component registry.
 	locks          lockMask                  // World locks.
+	hooks          componentHooks            // Component lifecycle hooks.
+	defaults       componentDefaults         // Component default values and constructors.
 	config         config                    // World configuration.
 }
 
@@ -112,17 +119,8 @@ func (w *World) newEntity(fn func(Entity), comps ...ID) Entity {
 		fn(entity)
 	}
 
-	if w.listener != nil {
-		var newRel *ID
-		if arch.HasRelationComponent {
-			newRel = &arch.RelationComponent
-		}
-		bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
-		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, EventTypes: bits})
-		}
-	}
+	w.callHooks(hookAdd, entity, comps)
+	w.notifyCreated(entity, arch, comps)
 	return entity
 }
 
@@ -165,6 +163,8 @@ func (w *World) NewEntityWith(comps ...Component) Entity {
 		w.copyTo(entity, c.ID, c.Comp)
 	}
 
+	w.callHooks(hookAdd, entity, ids)
+
 	if w.listener != nil {
 		var newRel *ID
 		if arch.HasRelationComponent {
@@ -172,8 +172,8 @@ func (w *World) NewEntityWith(comps ...Component) Entity {
 		}
 		bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
+		if trigger != 0 && (subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) || w.listeners.watched(entity, Entity{}, arch.RelationTarget)) {
+			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, NewTarget: arch.RelationTarget, EventTypes: bits})
 		}
 	}
 	return entity
@@ -190,6 +190,10 @@ func (w *World) RemoveEntity(entity Entity) {
 		panic("can't remove a dead entity")
 	}
 
+	if w.tx != nil {
+		w.tx.capture(entity.id)
+	}
+
 	index := &w.entities[entity.id]
 	oldArch := index.arch
 
@@ -205,21 +209,20 @@ func (w *World) RemoveEntity(entity Entity) {
 
 		bits := subscription(false, true, false, len(oldIds) > 0, oldRel != nil, oldRel != nil)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, nil, &oldArch.Mask, w.listener.Components(), oldRel, nil) {
+		if trigger != 0 && (subscribes(trigger, nil, &oldArch.Mask, w.listener.Components(), oldRel, nil) || w.listeners.watched(entity, oldArch.RelationTarget, Entity{})) {
 			lock := w.lock()
 			w.listener.Notify(w, EntityEvent{Entity: entity, Removed: oldArch.Mask, RemovedIDs: oldIds, OldRelation: oldRel, OldTarget: oldArch.RelationTarget, EventTypes: bits})
 			w.unlock(lock)
 		}
 	}
 
-	swapped := oldArch.Remove(index.index)
+	w.callHooks(hookRemove, entity, oldArch.node.Ids)
+
+	w.removeRow(oldArch, index.index)
 
+	w.removeName(entity.id)
 	w.entityPool.Recycle(entity)
 
-	if swapped {
-		swapEntity := oldArch.GetEntity(index.index)
-		w.entities[swapEntity.id].index = index.index
-	}
 	index.arch = nil
 
 	if w.targetEntities.Get(entity.id) {
@@ -239,6 +242,7 @@ func (w *World) Alive(entity Entity) bool {
 // Returns nil if the entity has no such component.
 //
 // ⚠️ Important: The obtained pointer should not be stored persistently!
+// With build tag `debug`, stale pointers can be detected with [World.CheckPointer].
 //
 // Panics when called for a removed (and potentially recycled) entity.
 //
@@ -249,7 +253,7 @@ func (w *World) Get(entity Entity, comp ID) unsafe.Pointer {
 		panic("can't get component of a dead entity")
 	}
 	index := &w.entities[entity.id]
-	return index.arch.Get(index.index, comp)
+	return w.stampPointer(index.arch.Get(index.index, comp), index.arch, index.index, comp)
 }
 
 // GetUnchecked returns a pointer to the given component of an [Entity].
@@ -266,7 +270,20 @@ func (w *World) Get(entity Entity, comp ID) unsafe.Pointer {
 // See also [github.com/mlange-42/arche/generic.Map.Get] for a generic variant.
 func (w *World) GetUnchecked(entity Entity, comp ID) unsafe.Pointer {
 	index := &w.entities[entity.id]
-	return index.arch.Get(index.index, comp)
+	return w.stampPointer(index.arch.Get(index.index, comp), index.arch, index.index, comp)
+}
+
+// CheckPointer panics if a component pointer obtained from the world is stale,
+// i.e. if the underlying storage was reallocated or the entity's row was moved since the pointer was obtained.
+// Accepts typed pointers as well as [unsafe.Pointer].
+//
+// Stale pointer detection is only enabled with build tag `debug`.
+// It covers pointers obtained via [World.Get], [World.GetUnchecked] and [Query.Get],
+// as well as the generic variants that use them.
+// Pointers not obtained from the world are ignored.
+// Without the build tag, this method does nothing.
+func (w *World) CheckPointer(ptr any) {
+	w.checkPointer(ptr)
 }
 
 // Has returns whether an [Entity] has a given component.
@@ -368,6 +385,7 @@ func (w *World) Assign(entity Entity, comps ...Component) {
 // The passed component must be a pointer.
 // Returns a pointer to the assigned memory.
 // The passed in pointer is not a valid reference to that memory!
+// Calls the component's OnSet hook, if any (see [RegisterHooks]).
 //
 // Panics:
 //   - when called for a removed (and potentially recycled) entity.
@@ -376,7 +394,9 @@ func (w *World) Assign(entity Entity, comps ...Component) {
 //
 // See also [github.com/mlange-42/arche/generic.Map.Set] for a generic variant.
 func (w *World) Set(entity Entity, id ID, comp interface{}) unsafe.Pointer {
-	return w.copyTo(entity, id, comp)
+	ptr := w.copyTo(entity, id, comp)
+	w.callHooks(hookSet, entity, []ID{id})
+	return ptr
 }
 
 // Remove removes components from an entity.
@@ -437,12 +457,18 @@ func (w *World) ExchangeFn(entity Entity, add []ID, rem []ID, fn func(Entity)) {
 // Accelerates re-populating the world by a factor of 2-3.
 func (w *World) Reset() {
 	w.checkLocked()
+	if w.tx != nil {
+		panic("can't reset the world during a transaction")
+	}
 
 	w.entities = w.entities[:1]
 	w.targetEntities.Reset()
 	w.entityPool.Reset()
+	w.names.Reset()
 	w.locks.Reset()
 	w.resources.reset()
+	w.listeners.ResetWatches()
+	w.updateListener()
 
 	len := w.nodes.Len()
 	var i int32
@@ -460,16 +486,35 @@ func (w *World) Reset() {
 // A query can iterate through its entities only once, and can't be used anymore afterwards.
 //
 // To create a [Filter] for querying, see [All], [Mask.Without], [Mask.Exclusive] and [RelationFilter].
+// To filter by component values, see [PredicateFilter].
 //
 // For type-safe generics queries, see package [github.com/mlange-42/arche/generic].
 // For advanced filtering, see package [github.com/mlange-42/arche/filter].
 func (w *World) Query(filter Filter) Query {
-	l := w.lock()
-	if cached, ok := filter.(*CachedFilter); ok {
-		return newCachedQuery(w, cached.filter, l, w.filterCache.get(cached).Archetypes.pointers)
-	}
+	return w.query(filter, false)
+}
 
-	return newQuery(w, filter, l, w.nodePointers)
+// QueryRead creates a read-only [Query] iterator.
+//
+// In contrast to [World.Query], read-only queries can be created and iterated
+// concurrently from multiple goroutines, e.g. by parallel systems that only read components.
+// They lock the world by an atomic counter instead of a lock bit,
+// and thus their number is not limited by [MaskTotalBits].
+// Creating and iterating a read-only query does not modify the world's internal state.
+//
+// Like other queries, read-only queries lock the world to prevent changes to component compositions.
+// They must not be used concurrently with operations that modify the world,
+// including the creation of regular queries, and registering filters in the [Cache].
+//
+// Components obtained from a read-only query must not be modified.
+// Accordingly, read-only queries are not captured by transactions (see [World.Begin]),
+// and they are not recorded in the query statistics of [World.Stats] with build tag `profile`.
+// With build tag `debug`, pointers handed out while read-only queries are open are not checked by [World.CheckPointer].
+// Predicates of a [PredicateFilter] must be safe for concurrent use.
+//
+// For type-safe generics queries, see e.g. [github.com/mlange-42/arche/generic.Filter1.QueryRead].
+func (w *World) QueryRead(filter Filter) Query {
+	return w.query(filter, true)
 }
 
 // Resources of the world.
@@ -485,6 +530,7 @@ func (w *World) Resources() *Resources {
 func (w *World) Cache() *Cache {
 	if w.filterCache.getArchetypes == nil {
 		w.filterCache.getArchetypes = w.getArchetypes
+		w.filterCache.unregistered = w.profileUnregister
 	}
 	return &w.filterCache
 }
@@ -532,9 +578,27 @@ func (w *World) Ids(entity Entity) []ID {
 // The listener is immediately called on every [ecs.Entity] change.
 // Replaces the current listener. Call with nil to remove a listener.
 //
+// Listeners added with [World.AddListener] are not affected.
+// A replaced listener keeps its position in the order of notification.
+//
 // For details, see [EntityEvent], [Listener] and sub-package [event].
 func (w *World) SetListener(listener Listener) {
-	w.listener = listener
+	if listener == nil {
+		w.listeners.Remove(0)
+	} else {
+		w.listeners.Set(listener, 0, 0)
+	}
+	w.updateListener()
+}
+
+// SetResourceListener sets a [ResourceListener] for the world.
+// The listener is immediately called on resource addition, removal and changes.
+// Replaces the current resource listener. Call with nil to remove a listener.
+//
+// For details, see [ResourceEvent], [ResourceListener] and sub-package [event].
+func (w *World) SetResourceListener(listener ResourceListener) {
+	w.resources.listener = listener
+	w.resources.world = w
 }
 
 // Stats reports statistics for inspecting the World.
@@ -548,6 +612,7 @@ func (w *World) Stats() *stats.World {
 		Total:    w.entityPool.Cap(),
 		Recycled: w.entityPool.Available(),
 		Capacity: w.entityPool.TotalCap(),
+		Named:    w.names.Len(),
 	}
 
 	compCount := len(w.registry.Components)
@@ -577,6 +642,9 @@ func (w *World) Stats() *stats.World {
 		}
 	}
 
+	w.updateComponentStats(types)
+	w.profileStats()
+
 	w.stats.ComponentCount = compCount
 	w.stats.ComponentTypes = types
 	w.stats.Locked = w.IsLocked()
@@ -587,11 +655,20 @@ func (w *World) Stats() *stats.World {
 	return &w.stats
 }
 
-// DumpEntities dumps entity information into an [EntityDump] object.
+// ResetQueryStats resets the query statistics reported in [stats.World.Queries].
+//
+// Query statistics are only recorded with build tag `profile`.
+// Without the tag, this method does nothing.
+func (w *World) ResetQueryStats() {
+	w.profileReset()
+}
+
+// DumpEntities dumps entity information, including entity names, into an [EntityDump] object.
 // This dump can be used with [World.LoadEntities] to set the World's entity state.
 //
 // For world serialization with components and resources, see module [github.com/mlange-42/arche-serde].
 func (w *World) DumpEntities() EntityDump {
+	w.flushReserved()
 	alive := []uint32{}
 
 	filter := All()
@@ -606,6 +683,12 @@ func (w *World) DumpEntities() EntityDump {
 		Next:      uint32(w.entityPool.next),
 		Available: w.entityPool.available,
 	}
+	if w.names.Len() > 0 {
+		data.Names = make(map[uint32]string, w.names.Len())
+		for id, name := range w.names.byEntity {
+			data.Names[uint32(id)] = name
+		}
+	}
 
 	return data
 }
@@ -617,12 +700,17 @@ func (w *World) DumpEntities() EntityDump {
 // The resulting world will have the same entities (in terms of ID, generation and alive state)
 // as the original world. This is necessary for proper serialization of entity relations.
 // However, the entities will not have any components.
+// Entity names are restored as well.
 //
-// Panics if the world has any dead or alive entities.
+// Panics if the world has any dead or alive entities, or if the dump contains names of dead entities.
 //
 // For world serialization with components and resources, see module [github.com/mlange-42/arche-serde].
 func (w *World) LoadEntities(data *EntityDump) {
 	w.checkLocked()
+	if w.tx != nil {
+		panic("can't load entities during a transaction")
+	}
+	w.flushReserved()
 
 	if len(w.entityPool.entities) > 1 || w.entityPool.available > 0 {
 		panic("can set entity data only on a fresh or reset world")
@@ -636,6 +724,11 @@ func (w *World) LoadEntities(data *EntityDump) {
 	w.entityPool.entities = entities
 	w.entityPool.next = eid(data.Next)
 	w.entityPool.available = data.Available
+	for _, e := range entities {
+		if e.id == reservedID {
+			w.entityPool.open++
+		}
+	}
 
 	w.entities = make([]entityIndex, len(data.Entities), capacity)
 	w.targetEntities = bitSet{}
@@ -647,4 +740,11 @@ func (w *World) LoadEntities(data *EntityDump) {
 		archIdx := arch.Alloc(entity)
 		w.entities[entity.id] = entityIndex{arch: arch, index: archIdx}
 	}
+
+	for id, name := range data.Names {
+		if int(id) >= len(w.entities) || w.entities[id].arch == nil {
+			panic("can't load the name of a dead entity")
+		}
+		w.SetName(w.entityPool.entities[id], name)
+	}
 }
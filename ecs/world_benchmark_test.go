@@ -397,6 +397,52 @@ func BenchmarkWorldNewEntityEvent_1000(b *testing.B) {
 	}
 }
 
+func BenchmarkWorldNewEntitiesBatchEvent_1000(b *testing.B) {
+	b.StopTimer()
+
+	world := NewWorld()
+
+	posID := ComponentID[Position](&world)
+	filterPos := All(posID)
+
+	builder := NewBuilder(&world, posID)
+	builder.NewBatch(1000)
+	world.Batch().RemoveEntities(filterPos)
+
+	listener := dummyBatchListener{}
+	world.SetListener(&listener)
+
+	for i := 0; i < b.N; i++ {
+		b.StartTimer()
+		builder.NewBatch(1000)
+		b.StopTimer()
+		world.Batch().RemoveEntities(filterPos)
+	}
+}
+
+func BenchmarkWorldNewEntitiesEvent_1000(b *testing.B) {
+	b.StopTimer()
+
+	world := NewWorld()
+
+	posID := ComponentID[Position](&world)
+	filterPos := All(posID)
+
+	builder := NewBuilder(&world, posID)
+	builder.NewBatch(1000)
+	world.Batch().RemoveEntities(filterPos)
+
+	listener := dummyListener{}
+	world.SetListener(&listener)
+
+	for i := 0; i < b.N; i++ {
+		b.StartTimer()
+		builder.NewBatch(1000)
+		b.StopTimer()
+		world.Batch().RemoveEntities(filterPos)
+	}
+}
+
 func BenchmarkWorldNewEntityEventCallback_1000(b *testing.B) {
 	b.StopTimer()
 
@@ -818,6 +864,16 @@ func BenchmarkWorldExchangeBatchEventCallback_1000(b *testing.B) {
 	_ = temp
 }
 
+type dummyBatchListener struct {
+	dummyListener
+	count int
+}
+
+func (l *dummyBatchListener) NotifyBatch(w *World, evt BatchEvent) {
+	l.temp = evt.EventTypes
+	l.count += evt.Len()
+}
+
 type dummyListener struct {
 	temp event.Subscription
 }
@@ -92,6 +92,7 @@ func TestWorldListener(t *testing.T) {
 		Entity:      e0,
 		OldRelation: &relID,
 		NewRelation: &relID,
+		NewTarget:   e1,
 		EventTypes:  event.TargetChanged,
 	}, events[len(events)-1])
 
@@ -163,6 +164,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		Added:       All(posID, relID),
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
+		NewTarget:   parent,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
 	}, events[len(events)-1])
 
@@ -175,6 +177,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		Added:       All(posID, relID),
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
+		NewTarget:   parent,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
 	}, events[len(events)-1])
 
@@ -214,6 +217,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		Added:       All(posID, relID),
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
+		NewTarget:   parent,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
 	}, events[len(events)-1])
 
@@ -226,6 +230,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		Added:       All(posID, relID),
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
+		NewTarget:   parent,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
 	}, events[len(events)-1])
 }
@@ -3,6 +3,7 @@ package ecs_test
 import (
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/mlange-42/arche/ecs"
 	"github.com/mlange-42/arche/ecs/event"
@@ -24,6 +25,37 @@ func ExampleTypeID() {
 	// Output:
 }
 
+func ExampleRegisterHooks() {
+	world := ecs.NewWorld()
+	posID := ecs.RegisterHooks[Position](&world,
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { fmt.Println("added", entity) },
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { fmt.Println("removed", entity) },
+		nil,
+	)
+
+	entity := world.NewEntity(posID)
+	world.RemoveEntity(entity)
+	// Output: added {1 0}
+	// removed {1 0}
+}
+
+func ExampleRegisterDefault() {
+	world := ecs.NewWorld()
+	posID := ecs.RegisterDefault(&world, Position{X: 100, Y: 100})
+
+	entity := world.NewEntity(posID)
+	fmt.Println(*(*Position)(world.Get(entity, posID)))
+	// Output: {100 100}
+}
+
+func ExampleRegisterConstructor() {
+	world := ecs.NewWorld()
+	posID := ecs.RegisterConstructor(&world, func() Position { return Position{X: 100, Y: 100} })
+
+	world.Batch().New(10, posID)
+	// Output:
+}
+
 func ExampleResourceID() {
 	world := ecs.NewWorld()
 	resID := ecs.ResourceID[Position](&world)
@@ -220,6 +252,34 @@ func ExampleWorld_Query() {
 	// Output:
 }
 
+func ExampleWorld_QueryRead() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	for i := 0; i < 100; i++ {
+		entity := world.NewEntity(posID)
+		(*Position)(world.Get(entity, posID)).X = i
+	}
+
+	filter := ecs.All(posID)
+	sums := make([]int, 4)
+	wg := sync.WaitGroup{}
+	for i := range sums {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			query := world.QueryRead(&filter)
+			for query.Next() {
+				sums[i] += (*Position)(query.Get(posID)).X
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	fmt.Println(sums)
+	// Output: [4950 4950 4950 4950]
+}
+
 func ExampleWorld_Relations() {
 	world := ecs.NewWorld()
 
@@ -303,11 +363,28 @@ func ExampleWorld_SetListener() {
 	// Output: {1 0}
 }
 
+func ExampleWorld_SetName() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	player := world.NewEntity(posID)
+	world.SetName(player, "player")
+
+	entity, ok := world.Lookup("player")
+	fmt.Println(entity == player, ok, world.Name(entity))
+
+	world.RemoveEntity(player)
+	_, ok = world.Lookup("player")
+	fmt.Println(ok)
+	// Output: true true player
+	// false
+}
+
 func ExampleWorld_Stats() {
 	world := ecs.NewWorld()
 	stats := world.Stats()
 	fmt.Println(stats.Entities.String())
-	// Output: Entities -- Used: 0, Recycled: 0, Total: 0, Capacity: 128
+	// Output: Entities -- Used: 0, Recycled: 0, Total: 0, Capacity: 128, Named: 0
 }
 
 // TestListener for all [EntityEvent]s.
@@ -6,6 +6,7 @@ import (
 	"unsafe"
 
 	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/ecs/stats"
 )
 
 // fromConfig creates a new [World] from a [config].
@@ -27,6 +28,7 @@ func fromConfig(conf config) World {
 		relationNodes:  []*archNode{},
 		locks:          lockMask{},
 		listener:       nil,
+		listeners:      newWorldListener(),
 		resources:      newResources(),
 		filterCache:    newCache(),
 	}
@@ -56,11 +58,13 @@ func (w *World) newEntityTarget(targetID ID, target Entity, comps ...ID) Entity
 		w.targetEntities.Set(target.id, true)
 	}
 
+	w.callHooks(hookAdd, entity, comps)
+
 	if w.listener != nil {
 		bits := subscription(true, false, len(comps) > 0, false, true, true)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, &targetID) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: &targetID, EventTypes: bits})
+		if trigger != 0 && (subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, &targetID) || w.listeners.watched(entity, Entity{}, arch.RelationTarget)) {
+			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: &targetID, NewTarget: arch.RelationTarget, EventTypes: bits})
 		}
 	}
 	return entity
@@ -93,11 +97,13 @@ func (w *World) newEntityTargetWith(targetID ID, target Entity, comps ...Compone
 		w.copyTo(entity, c.ID, c.Comp)
 	}
 
+	w.callHooks(hookAdd, entity, ids)
+
 	if w.listener != nil {
 		bits := subscription(true, false, len(comps) > 0, false, true, true)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, &targetID) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: &targetID, EventTypes: bits})
+		if trigger != 0 && (subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, &targetID) || w.listeners.watched(entity, Entity{}, arch.RelationTarget)) {
+			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: &targetID, NewTarget: arch.RelationTarget, EventTypes: bits})
 		}
 	}
 	return entity
@@ -115,20 +121,33 @@ func (w *World) newEntities(count int, targetID ID, hasTarget bool, target Entit
 		}
 		bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
-			cnt := uint32(count)
-			var i uint32
-			for i = 0; i < cnt; i++ {
-				idx := startIdx + i
-				entity := arch.GetEntity(idx)
-				w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, EventTypes: bits})
-			}
+		if trigger != 0 && (subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) || w.listeners.watchedRange(Entity{}, arch.RelationTarget)) {
+			w.notifyNewEntities(arch, startIdx, uint32(count), comps, newRel, bits)
 		}
 	}
 
 	return arch, startIdx
 }
 
+// notifies the listener about entities created in a batch.
+//
+// Listeners implementing [BatchListener] are notified once.
+func (w *World) notifyNewEntities(arch *archetype, start uint32, count uint32, ids []ID, newRel *ID, bits event.Subscription) {
+	if bl, ok := w.listener.(BatchListener); ok {
+		bl.NotifyBatch(w, BatchEvent{
+			Added: arch.Mask, AddedIDs: ids, NewRelation: newRel,
+			Mask: arch.Mask, NewTarget: arch.RelationTarget, EventTypes: bits,
+			world: w, archetype: arch, start: start, end: start + count,
+		})
+		return
+	}
+	var i uint32
+	for i = 0; i < count; i++ {
+		entity := arch.GetEntity(start + i)
+		w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, NewTarget: arch.RelationTarget, EventTypes: bits})
+	}
+}
+
 // Creates new entities and returns a query over them.
 // Used via [World.Batch].
 func (w *World) newEntitiesQuery(count int, targetID ID, hasTarget bool, target Entity, comps ...ID) Query {
@@ -160,14 +179,8 @@ func (w *World) newEntitiesWith(count int, targetID ID, hasTarget bool, target E
 		}
 		bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
-			var i uint32
-			cnt := uint32(count)
-			for i = 0; i < cnt; i++ {
-				idx := startIdx + i
-				entity := arch.GetEntity(idx)
-				w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
-			}
+		if trigger != 0 && (subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) || w.listeners.watchedRange(Entity{}, arch.RelationTarget)) {
+			w.notifyNewEntities(arch, startIdx, uint32(count), ids, newRel, bits)
 		}
 	}
 
@@ -217,6 +230,7 @@ func (w *World) newEntitiesNoNotify(count int, targetID ID, hasTarget bool, targ
 
 	startIdx := arch.Len()
 	w.createEntities(arch, uint32(count))
+	w.callHooksBatch(hookAdd, arch, startIdx, arch.Len(), comps)
 
 	return arch, startIdx
 }
@@ -261,14 +275,20 @@ func (w *World) newEntitiesWithNoNotify(count int, targetID ID, hasTarget bool,
 			w.copyTo(entity, c.ID, c.Comp)
 		}
 	}
+	w.callHooksBatch(hookAdd, arch, startIdx, arch.Len(), ids)
 
 	return arch, startIdx
 }
 
 // createEntity creates an Entity and adds it to the given archetype.
 func (w *World) createEntity(arch *archetype) Entity {
+	w.flushReserved()
+	if w.tx != nil {
+		w.tx.captureNext()
+	}
 	entity := w.entityPool.Get()
 	idx := arch.Alloc(entity)
+	w.applyDefaults(arch, idx, arch.node.Ids)
 	len := len(w.entities)
 	if int(entity.id) == len {
 		w.entities = append(w.entities, entityIndex{arch: arch, index: idx})
@@ -280,8 +300,25 @@ func (w *World) createEntity(arch *archetype) Entity {
 	return entity
 }
 
+// notifyCreated notifies the listener about the creation of an entity.
+func (w *World) notifyCreated(entity Entity, arch *archetype, comps []ID) {
+	if w.listener == nil {
+		return
+	}
+	var newRel *ID
+	if arch.HasRelationComponent {
+		newRel = &arch.RelationComponent
+	}
+	bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
+	trigger := w.listener.Subscriptions() & bits
+	if trigger != 0 && (subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) || w.listeners.watched(entity, Entity{}, arch.RelationTarget)) {
+		w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, NewTarget: arch.RelationTarget, EventTypes: bits})
+	}
+}
+
 // createEntity creates multiple Entities and adds them to the given archetype.
 func (w *World) createEntities(arch *archetype, count uint32) {
+	w.flushReserved()
 	startIdx := arch.Len()
 	arch.AllocN(count)
 
@@ -305,11 +342,15 @@ func (w *World) createEntities(arch *archetype, count uint32) {
 	var i uint32
 	for i = 0; i < count; i++ {
 		idx := startIdx + i
+		if w.tx != nil {
+			w.tx.captureNext()
+		}
 		entity := w.entityPool.Get()
 		arch.SetEntity(idx, entity)
 		w.entities[entity.id] = entityIndex{arch: arch, index: idx}
 		w.targetEntities.Set(entity.id, false)
 	}
+	w.applyDefaultsRange(arch, startIdx, count, arch.node.Ids)
 }
 
 // RemoveEntities removes and recycles all entities matching a filter.
@@ -321,14 +362,15 @@ func (w *World) createEntities(arch *archetype, count uint32) {
 func (w *World) removeEntities(filter Filter) int {
 	w.checkLocked()
 
+	arches := w.getArchetypes(filter)
+
 	lock := w.lock()
 
 	var bits event.Subscription
-	var listen bool
+	var listen, watched bool
 
 	var count uint32
 
-	arches := w.getArchetypes(filter)
 	numArches := int32(len(arches))
 	var i int32
 	for i = 0; i < numArches; i++ {
@@ -339,6 +381,9 @@ func (w *World) removeEntities(filter Filter) int {
 		}
 
 		count += ln
+		if w.tx != nil {
+			w.tx.captureArchetype(arch, ln)
+		}
 
 		var oldRel *ID
 		var oldIds []ID
@@ -352,14 +397,16 @@ func (w *World) removeEntities(filter Filter) int {
 			bits = subscription(false, true, false, len(oldIds) > 0, oldRel != nil, oldRel != nil)
 			trigger := w.listener.Subscriptions() & bits
 			listen = trigger != 0 && subscribes(trigger, nil, &arch.Mask, w.listener.Components(), oldRel, nil)
+			watched = trigger != 0 && w.listeners.watchCount > 0
 		}
 
 		var j uint32
 		for j = 0; j < ln; j++ {
 			entity := arch.GetEntity(j)
-			if listen {
+			if listen || (watched && w.listeners.watched(entity, arch.RelationTarget, Entity{})) {
 				w.listener.Notify(w, EntityEvent{Entity: entity, Removed: arch.Mask, RemovedIDs: oldIds, OldRelation: oldRel, OldTarget: arch.RelationTarget, EventTypes: bits})
 			}
+			w.callHooks(hookRemove, entity, arch.node.Ids)
 			index := &w.entities[entity.id]
 			index.arch = nil
 
@@ -368,6 +415,7 @@ func (w *World) removeEntities(filter Filter) int {
 				w.targetEntities.Set(entity.id, false)
 			}
 
+			w.removeName(entity.id)
 			w.entityPool.Recycle(entity)
 		}
 		arch.Reset()
@@ -392,6 +440,7 @@ func (w *World) assign(entity Entity, relation ID, hasRelation bool, target Enti
 	for _, c := range comps {
 		w.copyTo(entity, c.ID, c.Comp)
 	}
+	w.callHooks(hookAdd, entity, ids)
 	if w.listener != nil {
 		w.notifyExchange(arch, oldMask, entity, ids, nil, oldTarget, oldRel)
 	}
@@ -406,6 +455,7 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relation ID, hasRela
 		if fn != nil {
 			fn(entity)
 		}
+		w.callHooks(hookAdd, entity, add)
 		w.notifyExchange(arch, oldMask, entity, add, rem, oldTarget, oldRel)
 		return
 	}
@@ -413,6 +463,7 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relation ID, hasRela
 	if fn != nil {
 		fn(entity)
 	}
+	w.callHooks(hookAdd, entity, add)
 }
 
 // perform exchange operation without notifying listeners.
@@ -429,6 +480,9 @@ func (w *World) exchangeNoNotify(entity Entity, add []ID, rem []ID, relation ID,
 		}
 		return nil, nil, Entity{}, nil
 	}
+	if w.tx != nil {
+		w.tx.capture(entity.id)
+	}
 	index := &w.entities[entity.id]
 	oldArch := index.arch
 
@@ -459,6 +513,8 @@ func (w *World) exchangeNoNotify(entity Entity, add []ID, rem []ID, relation ID,
 
 	oldIDs := oldArch.Components()
 
+	w.callHooks(hookRemove, entity, rem)
+
 	arch := w.findOrCreateArchetype(oldArch, add, rem, target)
 	newIndex := arch.Alloc(entity)
 
@@ -468,13 +524,9 @@ func (w *World) exchangeNoNotify(entity Entity, add []ID, rem []ID, relation ID,
 			arch.SetPointer(newIndex, id, comp)
 		}
 	}
+	w.applyDefaults(arch, newIndex, add)
 
-	swapped := oldArch.Remove(index.index)
-
-	if swapped {
-		swapEntity := oldArch.GetEntity(index.index)
-		w.entities[swapEntity.id].index = index.index
-	}
+	w.removeRow(oldArch, index.index)
 	w.entities[entity.id] = entityIndex{arch: arch, index: newIndex}
 
 	var oldRel *ID
@@ -510,11 +562,11 @@ func (w *World) notifyExchange(arch *archetype, oldMask *Mask, entity Entity, ad
 		changed := oldMask.Xor(&arch.Mask)
 		added := arch.Mask.And(&changed)
 		removed := oldMask.And(&changed)
-		if subscribes(trigger, &added, &removed, w.listener.Components(), oldRel, newRel) {
+		if subscribes(trigger, &added, &removed, w.listener.Components(), oldRel, newRel) || w.listeners.watched(entity, oldTarget, arch.RelationTarget) {
 			w.listener.Notify(w,
 				EntityEvent{Entity: entity, Added: added, Removed: removed,
 					AddedIDs: add, RemovedIDs: rem, OldRelation: oldRel, NewRelation: newRel,
-					OldTarget: oldTarget, EventTypes: bits},
+					OldTarget: oldTarget, NewTarget: arch.RelationTarget, EventTypes: bits},
 			)
 		}
 	}
@@ -633,6 +685,11 @@ func (w *World) exchangeArch(oldArch *archetype, oldArchLen uint32, add []ID, re
 		}
 	}
 
+	if w.tx != nil {
+		w.tx.captureArchetype(oldArch, oldArchLen)
+	}
+	w.callHooksBatch(hookRemove, oldArch, 0, oldArchLen, rem)
+
 	arch := w.findOrCreateArchetype(oldArch, add, rem, target)
 
 	startIdx := arch.Len()
@@ -652,6 +709,8 @@ func (w *World) exchangeArch(oldArch *archetype, oldArchLen uint32, add []ID, re
 	for _, id := range oldIDs {
 		arch.CopyFrom(oldArch, id, startIdx)
 	}
+	w.applyDefaultsRange(arch, startIdx, count, add)
+	w.callHooksBatch(hookAdd, arch, startIdx, startIdx+count, add)
 
 	if !target.IsZero() {
 		w.targetEntities.Set(target.id, true)
@@ -724,6 +783,9 @@ func (w *World) setRelation(entity Entity, comp ID, target Entity) {
 	if oldArch.RelationTarget == target {
 		return
 	}
+	if w.tx != nil {
+		w.tx.capture(entity.id)
+	}
 
 	arch, ok := oldArch.node.GetArchetype(target)
 	if !ok {
@@ -736,12 +798,7 @@ func (w *World) setRelation(entity Entity, comp ID, target Entity) {
 		arch.SetPointer(newIndex, id, comp)
 	}
 
-	swapped := oldArch.Remove(index.index)
-
-	if swapped {
-		swapEntity := oldArch.GetEntity(index.index)
-		w.entities[swapEntity.id].index = index.index
-	}
+	w.removeRow(oldArch, index.index)
 	w.entities[entity.id] = entityIndex{arch: arch, index: newIndex}
 
 	if !target.IsZero() {
@@ -753,8 +810,8 @@ func (w *World) setRelation(entity Entity, comp ID, target Entity) {
 
 	if w.listener != nil {
 		trigger := w.listener.Subscriptions() & event.TargetChanged
-		if trigger != 0 && subscribes(trigger, nil, nil, w.listener.Components(), &comp, &comp) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, OldRelation: &comp, NewRelation: &comp, OldTarget: oldTarget, EventTypes: event.TargetChanged})
+		if trigger != 0 && (subscribes(trigger, nil, nil, w.listener.Components(), &comp, &comp) || w.listeners.watched(entity, oldTarget, target)) {
+			w.listener.Notify(w, EntityEvent{Entity: entity, OldRelation: &comp, NewRelation: &comp, OldTarget: oldTarget, NewTarget: target, EventTypes: event.TargetChanged})
 		}
 	}
 }
@@ -819,6 +876,9 @@ func (w *World) setRelationArch(oldArch *archetype, oldArchLen uint32, comp ID,
 	//}
 
 	oldIDs := oldArch.Components()
+	if w.tx != nil {
+		w.tx.captureArchetype(oldArch, oldArchLen)
+	}
 
 	arch, ok := oldArch.node.GetArchetype(target)
 	if !ok {
@@ -876,6 +936,39 @@ func (w *World) lock() uint8 {
 	return w.locks.Lock()
 }
 
+// query creates a [Query] iterator, optionally read-only. See [World.Query] and [World.QueryRead].
+func (w *World) query(filter Filter, readOnly bool) Query {
+	if pf, ok := filter.(*PredicateFilter); ok {
+		query := w.query(pf.Filter, readOnly)
+		if query.predicate != nil {
+			query.Close()
+			panic("predicate filters can't be nested")
+		}
+		query.predicate = pf
+		query.predicatePtrs = make([]unsafe.Pointer, len(pf.Components))
+		return query
+	}
+
+	var l uint8
+	if readOnly {
+		w.locks.LockRead()
+	} else {
+		l = w.lock()
+	}
+	var query Query
+	if cached, ok := filter.(*CachedFilter); ok {
+		query = newCachedQuery(w, cached.filter, l, w.filterCache.get(cached).Archetypes.pointers)
+	} else {
+		query = newQuery(w, filter, l, w.nodePointers)
+	}
+	if readOnly {
+		query.readOnly = true
+	} else {
+		w.profileQuery(&query, filter)
+	}
+	return query
+}
+
 // unlock unlocks the given lock bit.
 func (w *World) unlock(l uint8) {
 	w.locks.Unlock(l)
@@ -895,6 +988,9 @@ func (w *World) copyTo(entity Entity, id ID, comp interface{}) unsafe.Pointer {
 	if !w.Has(entity, id) {
 		panic("can't copy component into entity that has no such component type")
 	}
+	if w.tx != nil {
+		w.tx.capture(entity.id)
+	}
 	index := &w.entities[entity.id]
 	arch := index.arch
 
@@ -995,7 +1091,12 @@ func (w *World) createArchetypeNode(mask Mask, relation ID, hasRelation bool) *a
 	w.nodes.Add(newArchNode(mask, w.nodeData.Get(w.nodeData.Len()-1), relation, hasRelation, capInc, types))
 	nd := w.nodes.Get(w.nodes.Len() - 1)
 	w.relationNodes = append(w.relationNodes, nd)
-	w.nodePointers = append(w.nodePointers, nd)
+	if w.config.deterministic {
+		nd.SetDeterministic(true)
+		w.insertNodePointer(nd)
+	} else {
+		w.nodePointers = append(w.nodePointers, nd)
+	}
 
 	return nd
 }
@@ -1023,6 +1124,9 @@ func (w *World) createArchetype(node *archNode, target Entity, forStorage bool)
 
 // Returns all archetypes that match the given filter.
 func (w *World) getArchetypes(filter Filter) []*archetype {
+	if _, ok := filter.(*PredicateFilter); ok {
+		panic("predicate filters are not supported by batch operations")
+	}
 	if cached, ok := filter.(*CachedFilter); ok {
 		return w.filterCache.get(cached).Archetypes.pointers
 	}
@@ -1058,8 +1162,10 @@ func (w *World) getArchetypes(filter Filter) []*archetype {
 }
 
 // Removes the archetype if it is empty, and has a relation to a dead target.
+//
+// The archetype may already be removed, e.g. if a removed entity was its own relation target.
 func (w *World) cleanupArchetype(arch *archetype) {
-	if arch.Len() > 0 || !arch.node.HasRelation {
+	if arch.Len() > 0 || !arch.node.HasRelation || !arch.IsActive() {
 		return
 	}
 	target := arch.RelationTarget
@@ -1115,21 +1221,60 @@ func (w *World) resourceID(tp reflect.Type) ResID {
 	return ResID{id: id}
 }
 
+// updateComponentStats updates per-component statistics from the node statistics.
+func (w *World) updateComponentStats(types []reflect.Type) {
+	comps := w.stats.Components[:0]
+	for i, tp := range types {
+		comps = append(comps, stats.Component{
+			ID:         uint8(i),
+			Type:       tp,
+			ItemSize:   int(tp.Size()),
+			IsRelation: w.registry.IsRelation.Get(ID{id: uint8(i)}),
+		})
+	}
+	for i := range w.stats.Nodes {
+		node := &w.stats.Nodes[i]
+		if !node.IsActive {
+			continue
+		}
+		for _, id := range node.ComponentIDs {
+			comp := &comps[id]
+			comp.Entities += node.Size
+			comp.Archetypes += node.ActiveArchetypeCount
+			comp.Memory += node.Capacity * comp.ItemSize
+		}
+	}
+	w.stats.Components = comps
+}
+
 // closeQuery closes a query and unlocks the world.
 func (w *World) closeQuery(query *Query) {
+	if query.readOnly {
+		if query.archIndex == -2 {
+			panic("unbalanced unlock. Did you close a query that was already iterated?")
+		}
+		query.nodeIndex = -2
+		query.archIndex = -2
+		w.locks.UnlockRead()
+		return
+	}
 	query.nodeIndex = -2
 	query.archIndex = -2
 	w.unlock(query.lockBit)
+	w.profileClose(query)
 
 	if w.listener != nil {
-		if arch, ok := query.nodeArchetypes.(*batchArchetypes); ok {
+		if arch, ok := query.nodeArchetypes.(*batchArchetypes); ok && !arch.silent {
 			w.notifyQuery(arch)
 		}
 	}
 }
 
 // notifies the listener for all entities on a batch query.
+//
+// Listeners implementing [BatchListener] are notified once per archetype.
 func (w *World) notifyQuery(batchArch *batchArchetypes) {
+	batchListener, isBatch := w.listener.(BatchListener)
 	count := batchArch.Len()
 	var i int32
 	for i = 0; i < count; i++ {
@@ -1143,7 +1288,7 @@ func (w *World) notifyQuery(batchArch *batchArchetypes) {
 		event := EntityEvent{
 			Entity: Entity{}, Added: arch.Mask, Removed: Mask{}, AddedIDs: batchArch.Added, RemovedIDs: batchArch.Removed,
 			OldRelation: nil, NewRelation: newRel,
-			OldTarget: Entity{}, EventTypes: 0,
+			OldTarget: Entity{}, NewTarget: arch.RelationTarget, EventTypes: 0,
 		}
 
 		oldArch := batchArch.OldArchetype[i]
@@ -1171,8 +1316,18 @@ func (w *World) notifyQuery(batchArch *batchArchetypes) {
 		event.EventTypes = bits
 
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, &event.Added, &event.Removed, w.listener.Components(), event.OldRelation, event.NewRelation) {
+		if trigger != 0 && (subscribes(trigger, &event.Added, &event.Removed, w.listener.Components(), event.OldRelation, event.NewRelation) || w.listeners.watchedRange(event.OldTarget, event.NewTarget)) {
 			start, end := batchArch.StartIndex[i], batchArch.EndIndex[i]
+			if isBatch && start < end {
+				batchListener.NotifyBatch(w, BatchEvent{
+					OldRelation: event.OldRelation, NewRelation: event.NewRelation,
+					AddedIDs: event.AddedIDs, RemovedIDs: event.RemovedIDs,
+					Added: event.Added, Removed: event.Removed, Mask: arch.Mask,
+					OldTarget: event.OldTarget, NewTarget: event.NewTarget, EventTypes: bits,
+					world: w, archetype: arch, start: start, end: end,
+				})
+				continue
+			}
 			var e uint32
 			for e = start; e < end; e++ {
 				entity := arch.GetEntity(e)
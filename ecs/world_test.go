@@ -424,6 +424,7 @@ func TestWorldExchangeBatch(t *testing.T) {
 		Added:       All(posID, relID),
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
+		NewTarget:   target2,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
 	}, events[201])
 
@@ -467,6 +468,7 @@ func TestWorldExchangeBatch(t *testing.T) {
 		OldRelation: &relID,
 		NewRelation: &relID,
 		OldTarget:   target1,
+		NewTarget:   target1,
 		EventTypes:  event.ComponentAdded | event.ComponentRemoved,
 	}, events[501])
 
@@ -963,6 +965,7 @@ func TestWorldRelationSet(t *testing.T) {
 		OldRelation: &relID,
 		NewRelation: &relID,
 		OldTarget:   Entity{},
+		NewTarget:   targ,
 		EventTypes:  event.TargetChanged,
 	}, events[len(events)-1])
 
@@ -1199,6 +1202,22 @@ func TestWorldRelationRemove(t *testing.T) {
 	assert.False(t, world.nodes.Get(2).archetypes.Get(1).IsActive())
 }
 
+func TestWorldRelationRemoveSelfTarget(t *testing.T) {
+	w := NewWorld()
+	relID := ComponentID[testRelationA](&w)
+
+	e := w.NewEntity(relID)
+	w.Relations().Set(e, relID, e)
+	w.RemoveEntity(e)
+	assert.False(t, w.Alive(e))
+	assert.NoError(t, w.Validate())
+
+	e = w.NewEntity(relID)
+	w.Relations().Set(e, relID, e)
+	assert.Equal(t, 1, w.Batch().RemoveEntities(All(relID)))
+	assert.NoError(t, w.Validate())
+}
+
 func TestWorldRelationQuery(t *testing.T) {
 	world := NewWorld()
 
@@ -1539,6 +1558,26 @@ func TestWorldStats(t *testing.T) {
 	assert.Equal(t, 10, node.Archetypes[1].Size)
 	assert.Equal(t, 5, node.Archetypes[2].Size)
 
+	assert.Equal(t, 4, len(stats.Components))
+	pos := stats.Components[posID.id]
+	assert.Equal(t, posID.id, pos.ID)
+	assert.Equal(t, reflect.TypeOf(Position{}), pos.Type)
+	assert.Equal(t, int(reflect.TypeOf(Position{}).Size()), pos.ItemSize)
+	assert.False(t, pos.IsRelation)
+	assert.Equal(t, 3, pos.Entities)
+	assert.Equal(t, 2, pos.Archetypes)
+	assert.Equal(t, (stats.Nodes[1].Capacity+stats.Nodes[2].Capacity)*pos.ItemSize, pos.Memory)
+
+	rel := stats.Components[relID.id]
+	assert.True(t, rel.IsRelation)
+	assert.Equal(t, 25, rel.Entities)
+	assert.Equal(t, 3, rel.Archetypes)
+	assert.Equal(t, 0, rel.Memory)
+
+	vel := stats.Components[velID.id]
+	assert.Equal(t, 1, vel.Entities)
+	assert.Equal(t, 1, vel.Archetypes)
+
 	f := All(relID).Exclusive()
 	w.Batch().RemoveEntities(&f)
 	w.RemoveEntity(e0)
@@ -1546,6 +1585,12 @@ func TestWorldStats(t *testing.T) {
 
 	s = stats.String()
 	fmt.Println(s)
+
+	if !isProfile {
+		assert.Empty(t, stats.Queries)
+	}
+	w.ResetQueryStats()
+	assert.Empty(t, w.Stats().Queries)
 }
 
 func TestWorldResources(t *testing.T) {
@@ -1612,6 +1657,7 @@ func TestWorldBatchRemove(t *testing.T) {
 		Added:       All(rotID, relID),
 		AddedIDs:    []ID{rotID, relID},
 		NewRelation: &relID,
+		NewTarget:   target3,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
 	}, events[len(events)-1])
 
@@ -2126,3 +2172,21 @@ func TestWorldPointerStressTestAssign(t *testing.T) {
 		runtime.GC()
 	}
 }
+
+func TestWorldCheckPointer(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e0 := w.NewEntity(posID)
+	e1 := w.NewEntity(posID)
+
+	pos := (*Position)(w.Get(e1, posID))
+	assert.NotPanics(t, func() { w.CheckPointer(pos) })
+
+	w.RemoveEntity(e0)
+	if isDebug {
+		assert.Panics(t, func() { w.CheckPointer(pos) })
+	} else {
+		assert.NotPanics(t, func() { w.CheckPointer(pos) })
+	}
+}
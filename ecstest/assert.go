@@ -0,0 +1,160 @@
+package ecstest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// AssertAlive asserts that an entity is alive.
+func AssertAlive(t testing.TB, w *ecs.World, e ecs.Entity) bool {
+	t.Helper()
+	if !w.Alive(e) {
+		t.Errorf("expected entity %v to be alive", e)
+		return false
+	}
+	return true
+}
+
+// AssertDead asserts that an entity is not alive.
+func AssertDead(t testing.TB, w *ecs.World, e ecs.Entity) bool {
+	t.Helper()
+	if w.Alive(e) {
+		t.Errorf("expected entity %v to be dead", e)
+		return false
+	}
+	return true
+}
+
+// AssertEntityHas asserts that an entity is alive and has all the given components.
+func AssertEntityHas(t testing.TB, w *ecs.World, e ecs.Entity, comps ...ecs.ID) bool {
+	t.Helper()
+	if !AssertAlive(t, w, e) {
+		return false
+	}
+	missing := []ecs.ID{}
+	for _, id := range comps {
+		if !w.Has(e, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("expected entity %v to have components %s, but it is missing %s (has %s)",
+			e, typeNames(w, comps), typeNames(w, missing), typeNames(w, w.Ids(e)))
+		return false
+	}
+	return true
+}
+
+// AssertEntityHasNot asserts that an entity is alive and has none of the given components.
+func AssertEntityHasNot(t testing.TB, w *ecs.World, e ecs.Entity, comps ...ecs.ID) bool {
+	t.Helper()
+	if !AssertAlive(t, w, e) {
+		return false
+	}
+	present := []ecs.ID{}
+	for _, id := range comps {
+		if w.Has(e, id) {
+			present = append(present, id)
+		}
+	}
+	if len(present) > 0 {
+		t.Errorf("expected entity %v not to have components %s, but it has %s",
+			e, typeNames(w, comps), typeNames(w, present))
+		return false
+	}
+	return true
+}
+
+// AssertCount asserts that a filter matches the expected number of entities.
+func AssertCount(t testing.TB, w *ecs.World, filter ecs.Filter, expected int) bool {
+	t.Helper()
+	query := w.Query(filter)
+	count := query.Count()
+	query.Close()
+	if count != expected {
+		t.Errorf("expected filter to match %d entities, but it matches %d", expected, count)
+		return false
+	}
+	return true
+}
+
+// AssertComponent asserts that an entity's component equals the expected value.
+//
+// The expected value can be given as a value or as a pointer of the component type.
+// Values are compared via [reflect.DeepEqual].
+func AssertComponent(t testing.TB, w *ecs.World, e ecs.Entity, comp ecs.ID, expected any) bool {
+	t.Helper()
+	if !AssertEntityHas(t, w, e, comp) {
+		return false
+	}
+	tp := componentType(w, comp)
+	exp := reflect.ValueOf(expected)
+	if exp.Kind() == reflect.Pointer && exp.Type().Elem() == tp {
+		exp = exp.Elem()
+	}
+	if exp.Type() != tp {
+		t.Errorf("expected value for component %s of entity %v has type %s", tp.Name(), e, exp.Type())
+		return false
+	}
+	actual := componentValue(w, e, comp, tp)
+	if !reflect.DeepEqual(exp.Interface(), actual) {
+		t.Errorf("component %s of entity %v differs:\n  expected: %+v\n  actual:   %+v", tp.Name(), e, exp.Interface(), actual)
+		return false
+	}
+	return true
+}
+
+// AssertRelation asserts that an entity's relation component has the expected target.
+func AssertRelation(t testing.TB, w *ecs.World, e ecs.Entity, comp ecs.ID, target ecs.Entity) bool {
+	t.Helper()
+	if !AssertEntityHas(t, w, e, comp) {
+		return false
+	}
+	if actual := w.Relations().Get(e, comp); actual != target {
+		t.Errorf("expected relation %s of entity %v to target %v, but it targets %v",
+			componentType(w, comp).Name(), e, target, actual)
+		return false
+	}
+	return true
+}
+
+// AssertEqual asserts that two worlds have the same entities, components, relations and resources.
+// On failure, the report of [Diff] is given.
+func AssertEqual(t testing.TB, expected, actual *ecs.World) bool {
+	t.Helper()
+	if diff := Diff(expected, actual); diff != "" {
+		t.Errorf("worlds differ:\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// componentType returns the type of a component.
+func componentType(w *ecs.World, comp ecs.ID) reflect.Type {
+	info, ok := ecs.ComponentInfo(w, comp)
+	if !ok {
+		panic(fmt.Sprintf("component %v is not registered", comp))
+	}
+	return info.Type
+}
+
+// componentValue returns a copy of an entity's component, as an interface.
+func componentValue(w *ecs.World, e ecs.Entity, comp ecs.ID, tp reflect.Type) any {
+	if tp.Size() == 0 {
+		return reflect.Zero(tp).Interface()
+	}
+	return reflect.NewAt(tp, w.Get(e, comp)).Elem().Interface()
+}
+
+// typeNames returns the type names of components.
+func typeNames(w *ecs.World, comps []ecs.ID) string {
+	names := make([]string, len(comps))
+	for i, id := range comps {
+		names[i] = componentType(w, id).Name()
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
@@ -0,0 +1,116 @@
+package ecstest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecstest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X, Y float64
+}
+
+type Velocity struct {
+	X, Y float64
+}
+
+type Grid struct {
+	Cells []int
+}
+
+type ChildOf struct {
+	ecs.Relation
+}
+
+// mockT records failures of assertions.
+type mockT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *mockT) Helper() {}
+
+func (t *mockT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEntity(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	e := w.NewEntity(posID)
+	dead := w.NewEntity()
+	w.RemoveEntity(dead)
+
+	assert.True(t, ecstest.AssertAlive(t, &w, e))
+	assert.True(t, ecstest.AssertDead(t, &w, dead))
+	assert.True(t, ecstest.AssertEntityHas(t, &w, e, posID))
+	assert.True(t, ecstest.AssertEntityHasNot(t, &w, e, velID))
+
+	m := mockT{}
+	assert.False(t, ecstest.AssertAlive(&m, &w, dead))
+	assert.False(t, ecstest.AssertDead(&m, &w, e))
+	assert.False(t, ecstest.AssertEntityHas(&m, &w, e, posID, velID))
+	assert.False(t, ecstest.AssertEntityHasNot(&m, &w, e, posID, velID))
+	assert.False(t, ecstest.AssertEntityHas(&m, &w, dead, posID))
+
+	assert.Equal(t, []string{
+		"expected entity {2 0} to be alive",
+		"expected entity {1 0} to be dead",
+		"expected entity {1 0} to have components [Position, Velocity], but it is missing [Velocity] (has [Position])",
+		"expected entity {1 0} not to have components [Position, Velocity], but it has [Position]",
+		"expected entity {2 0} to be alive",
+	}, m.errors)
+}
+
+func TestAssertCount(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	w.Batch().New(10, posID)
+	w.Batch().New(5, posID, velID)
+
+	assert.True(t, ecstest.AssertCount(t, &w, ecs.All(posID), 15))
+	assert.True(t, ecstest.AssertCount(t, &w, ecs.All(velID), 5))
+
+	m := mockT{}
+	filter := ecs.All(posID).Without(velID)
+	assert.False(t, ecstest.AssertCount(&m, &w, &filter, 5))
+	assert.Equal(t, []string{"expected filter to match 5 entities, but it matches 10"}, m.errors)
+	assert.False(t, w.IsLocked())
+}
+
+func TestAssertComponent(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	relID := ecs.ComponentID[ChildOf](&w)
+
+	parent := w.NewEntity()
+	e := w.NewEntity(posID, relID)
+	pos := (*Position)(w.Get(e, posID))
+	pos.X = 1
+	w.Relations().Set(e, relID, parent)
+
+	assert.True(t, ecstest.AssertComponent(t, &w, e, posID, Position{X: 1}))
+	assert.True(t, ecstest.AssertComponent(t, &w, e, posID, &Position{X: 1}))
+	assert.True(t, ecstest.AssertRelation(t, &w, e, relID, parent))
+
+	m := mockT{}
+	assert.False(t, ecstest.AssertComponent(&m, &w, e, posID, Position{X: 2}))
+	assert.False(t, ecstest.AssertComponent(&m, &w, e, posID, Velocity{X: 1}))
+	assert.False(t, ecstest.AssertComponent(&m, &w, e, velID, Velocity{}))
+	assert.False(t, ecstest.AssertRelation(&m, &w, e, relID, ecs.Entity{}))
+
+	assert.Equal(t, []string{
+		"component Position of entity {2 0} differs:\n  expected: {X:2 Y:0}\n  actual:   {X:1 Y:0}",
+		"expected value for component Position of entity {2 0} has type ecstest_test.Velocity",
+		"expected entity {2 0} to have components [Velocity], but it is missing [Velocity] (has [Position, ChildOf])",
+		"expected relation ChildOf of entity {2 0} to target {0 0}, but it targets {1 0}",
+	}, m.errors)
+}
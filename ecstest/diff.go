@@ -0,0 +1,146 @@
+package ecstest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Diff returns a human-readable report of the differences between two worlds, with one line per difference.
+// Returns an empty string if there are no differences.
+//
+// Entities are matched by their ID and generation.
+// Components and resources are matched by their type, so that component and resource IDs may differ between the worlds.
+// Component and resource values are compared via [reflect.DeepEqual].
+// Relation targets are compared for relation components.
+func Diff(a, b *ecs.World) string {
+	d := differ{}
+	d.entities(a, b)
+	d.resources(a, b)
+	return strings.Join(d.lines, "\n")
+}
+
+// differ collects differences between two worlds.
+type differ struct {
+	lines []string
+}
+
+// addf adds a difference.
+func (d *differ) addf(format string, args ...any) {
+	d.lines = append(d.lines, fmt.Sprintf(format, args...))
+}
+
+// entities compares the entities of two worlds and their components.
+func (d *differ) entities(a, b *ecs.World) {
+	entA, entB := allEntities(a), allEntities(b)
+	idsB := make(map[ecs.Entity]bool, len(entB))
+	for _, e := range entB {
+		idsB[e] = true
+	}
+
+	for _, e := range entA {
+		if !idsB[e] {
+			d.addf("entity %v: only in first world, with components %s", e, typeNames(a, a.Ids(e)))
+			continue
+		}
+		delete(idsB, e)
+		d.components(a, b, e)
+	}
+	for _, e := range entB {
+		if idsB[e] {
+			d.addf("entity %v: only in second world, with components %s", e, typeNames(b, b.Ids(e)))
+		}
+	}
+}
+
+// components compares the components and relations of an entity that is alive in both worlds.
+func (d *differ) components(a, b *ecs.World, e ecs.Entity) {
+	compsB := map[reflect.Type]ecs.ID{}
+	for _, id := range b.Ids(e) {
+		compsB[componentType(b, id)] = id
+	}
+
+	for _, idA := range a.Ids(e) {
+		info, _ := ecs.ComponentInfo(a, idA)
+		tp := info.Type
+		idB, ok := compsB[tp]
+		if !ok {
+			d.addf("entity %v: component %s only in first world", e, tp.Name())
+			continue
+		}
+		delete(compsB, tp)
+
+		valA, valB := componentValue(a, e, idA, tp), componentValue(b, e, idB, tp)
+		if !reflect.DeepEqual(valA, valB) {
+			d.addf("entity %v: component %s differs: %+v != %+v", e, tp.Name(), valA, valB)
+		}
+		if info.IsRelation {
+			targetA, targetB := a.Relations().Get(e, idA), b.Relations().Get(e, idB)
+			if targetA != targetB {
+				d.addf("entity %v: relation %s targets differ: %v != %v", e, tp.Name(), targetA, targetB)
+			}
+		}
+	}
+
+	onlyB := make([]string, 0, len(compsB))
+	for tp := range compsB {
+		onlyB = append(onlyB, tp.Name())
+	}
+	sort.Strings(onlyB)
+	for _, name := range onlyB {
+		d.addf("entity %v: component %s only in second world", e, name)
+	}
+}
+
+// resources compares the resources of two worlds.
+func (d *differ) resources(a, b *ecs.World) {
+	resB := map[reflect.Type]ecs.ResID{}
+	for _, id := range ecs.ResourceIDs(b) {
+		if b.Resources().Has(id) {
+			tp, _ := ecs.ResourceType(b, id)
+			resB[tp] = id
+		}
+	}
+
+	for _, idA := range ecs.ResourceIDs(a) {
+		if !a.Resources().Has(idA) {
+			continue
+		}
+		tp, _ := ecs.ResourceType(a, idA)
+		idB, ok := resB[tp]
+		if !ok {
+			d.addf("resource %s: only in first world", tp.Name())
+			continue
+		}
+		delete(resB, tp)
+
+		valA, valB := a.Resources().Get(idA), b.Resources().Get(idB)
+		if !reflect.DeepEqual(valA, valB) {
+			d.addf("resource %s: differs: %+v != %+v", tp.Name(),
+				reflect.Indirect(reflect.ValueOf(valA)), reflect.Indirect(reflect.ValueOf(valB)))
+		}
+	}
+
+	onlyB := make([]string, 0, len(resB))
+	for tp := range resB {
+		onlyB = append(onlyB, tp.Name())
+	}
+	sort.Strings(onlyB)
+	for _, name := range onlyB {
+		d.addf("resource %s: only in second world", name)
+	}
+}
+
+// allEntities returns all alive entities of a world, sorted by ID.
+func allEntities(w *ecs.World) []ecs.Entity {
+	entities := []ecs.Entity{}
+	query := w.Query(ecs.All())
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].ID() < entities[j].ID() })
+	return entities
+}
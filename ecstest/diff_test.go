@@ -0,0 +1,82 @@
+package ecstest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	build := func(w *ecs.World, velFirst bool) []ecs.Entity {
+		var posID, velID ecs.ID
+		if velFirst {
+			velID = ecs.ComponentID[Velocity](w)
+			posID = ecs.ComponentID[Position](w)
+		} else {
+			posID = ecs.ComponentID[Position](w)
+			velID = ecs.ComponentID[Velocity](w)
+		}
+		relID := ecs.ComponentID[ChildOf](w)
+
+		parent := w.NewEntity()
+		e1 := w.NewEntity(posID, velID)
+		e2 := w.NewEntity(posID, relID)
+		w.Relations().Set(e2, relID, parent)
+		ecs.AddResource(w, &Grid{Cells: []int{1, 2, 3}})
+		return []ecs.Entity{parent, e1, e2}
+	}
+
+	w1 := ecs.NewWorld()
+	w2 := ecs.NewWorld()
+	build(&w1, false)
+	entities := build(&w2, true)
+
+	assert.Equal(t, "", ecstest.Diff(&w1, &w2))
+	assert.True(t, ecstest.AssertEqual(t, &w1, &w2))
+
+	posID := ecs.ComponentID[Position](&w2)
+	velID := ecs.ComponentID[Velocity](&w2)
+	relID := ecs.ComponentID[ChildOf](&w2)
+
+	(*Position)(w2.Get(entities[1], posID)).X = 5
+	w2.Remove(entities[1], velID)
+	w2.Relations().Set(entities[2], relID, ecs.Entity{})
+	w2.Add(entities[0], posID)
+	w2.NewEntity(velID)
+	ecs.GetResource[Grid](&w2).Cells[1] = 0
+
+	expected := "entity {1 0}: component Position only in second world\n" +
+		"entity {2 0}: component Position differs: {X:0 Y:0} != {X:5 Y:0}\n" +
+		"entity {2 0}: component Velocity only in first world\n" +
+		"entity {3 0}: relation ChildOf targets differ: {1 0} != {0 0}\n" +
+		"entity {4 0}: only in second world, with components [Velocity]\n" +
+		"resource Grid: differs: {Cells:[1 2 3]} != {Cells:[1 0 3]}"
+	assert.Equal(t, expected, ecstest.Diff(&w1, &w2))
+
+	m := mockT{}
+	assert.False(t, ecstest.AssertEqual(&m, &w1, &w2))
+	assert.Equal(t, []string{"worlds differ:\n" + expected}, m.errors)
+	assert.False(t, w1.IsLocked())
+	assert.False(t, w2.IsLocked())
+}
+
+func ExampleDiff() {
+	w1 := ecs.NewWorld()
+	w2 := ecs.NewWorld()
+
+	pos1 := ecs.ComponentID[Position](&w1)
+	pos2 := ecs.ComponentID[Position](&w2)
+
+	e := w1.NewEntity(pos1)
+	w2.NewEntity(pos2)
+	w2.NewEntity()
+
+	(*Position)(w1.Get(e, pos1)).X = 1
+
+	fmt.Println(ecstest.Diff(&w1, &w2))
+	// Output: entity {1 0}: component Position differs: {X:1 Y:0} != {X:0 Y:0}
+	// entity {2 0}: only in second world, with components []
+}
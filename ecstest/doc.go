@@ -0,0 +1,13 @@
+// Package ecstest provides test helpers for models built with Arche, an Entity Component System (ECS) for Go.
+//
+// The assertion functions like [AssertEntityHas], [AssertCount] and [AssertComponent]
+// report failures via [testing.TB], with messages that name the involved entities and component types.
+// [Diff] produces a human-readable report of the differences between two worlds,
+// and [AssertEqual] fails a test with that report.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package ecstest
@@ -0,0 +1,154 @@
+package events
+
+import "github.com/mlange-42/arche/ecs"
+
+// Bus is a typed, double-buffered event queue for user-defined events.
+//
+// Create one with [NewBus], or with [AddBus] to directly add it to the world's resources.
+//
+// Events are sent with [Bus.Send], and can be read by any number of independent [Reader] instances.
+// Further, [Subscriber] instances can be registered with [Bus.Subscribe],
+// which are notified immediately whenever an event is sent.
+//
+// # Double buffering
+//
+// A bus keeps the events of the current and the previous tick.
+// [Bus.Update] should be called exactly once per tick, e.g. by a dedicated system.
+// It moves the events of the current tick to the previous tick, and drops the events of the previous tick.
+// Thus, all readers that read at least once per tick see all events,
+// independent of the order in which systems are executed.
+type Bus[T any] struct {
+	world       *ecs.World      // The world, passed to subscribers.
+	previous    []T             // Events of the previous tick.
+	current     []T             // Events of the current tick.
+	subscribers []Subscriber[T] // Subscribers to notify on every event.
+	start       uint64          // Absolute index of the first event in previous.
+}
+
+// NewBus creates a new [Bus] for the given world.
+//
+// The bus is not added to the world's resources. See [AddBus] for that.
+func NewBus[T any](world *ecs.World) *Bus[T] {
+	return &Bus[T]{
+		world: world,
+	}
+}
+
+// AddBus creates a new [Bus] and adds it to the world's resources.
+//
+// Panics if there is already a bus for the event type.
+//
+// Get the bus from the world with [GetBus],
+// or faster with [github.com/mlange-42/arche/generic.Resource].
+func AddBus[T any](world *ecs.World) *Bus[T] {
+	bus := NewBus[T](world)
+	ecs.AddResource(world, bus)
+	return bus
+}
+
+// GetBus returns the [Bus] for the given event type from the world's resources.
+// Returns nil if there is no such bus.
+//
+// Uses reflection. For more efficient access, use [github.com/mlange-42/arche/generic.Resource].
+func GetBus[T any](world *ecs.World) *Bus[T] {
+	return ecs.GetResource[Bus[T]](world)
+}
+
+// Send an event.
+//
+// Subscribers are notified immediately, in the order of subscription.
+// They receive a pointer to a copy of the event, shared by all subscribers.
+// Thus, modifications by subscribers are not seen by readers.
+// Subscribers may send further events, and subscribe or unsubscribe.
+// Such changes to the subscribers take effect with the next event.
+//
+// Readers see the event during the current and the next tick.
+func (b *Bus[T]) Send(evt T) {
+	b.current = append(b.current, evt)
+	if len(b.subscribers) == 0 {
+		return
+	}
+	// Outline to avoid allocating the copy when there are no subscribers.
+	b.notify(evt)
+}
+
+// notify notifies all subscribers about an event.
+//
+// Iterates over the subscribers at the time of sending, as [Bus.Unsubscribe] does not modify them in place.
+// The event is passed as a pointer to a copy, as re-entrant calls to [Bus.Send] may reallocate the event buffer.
+func (b *Bus[T]) notify(evt T) {
+	for _, s := range b.subscribers {
+		s.Notify(b.world, &evt)
+	}
+}
+
+// Subscribe adds a [Subscriber] that is notified immediately on every sent event.
+func (b *Bus[T]) Subscribe(s Subscriber[T]) {
+	b.subscribers = append(b.subscribers, s)
+}
+
+// Unsubscribe removes a [Subscriber].
+// Subscribers are compared by equality, so they should be pointers.
+//
+// When called during the notification about an event, the subscriber may still be notified about that event.
+//
+// Returns whether the subscriber was found.
+func (b *Bus[T]) Unsubscribe(s Subscriber[T]) bool {
+	for i, sub := range b.subscribers {
+		if sub == s {
+			// Copy instead of shifting in place, as an ongoing notification may iterate the subscribers.
+			b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Reader creates a new [Reader] for the bus.
+//
+// The reader starts with the oldest event still held by the bus.
+func (b *Bus[T]) Reader() *Reader[T] {
+	return &Reader[T]{
+		bus:    b,
+		cursor: b.start,
+	}
+}
+
+// Update advances the bus by one tick.
+// Events of the previous tick are dropped, and events of the current tick become the previous tick's.
+//
+// Should be called exactly once per tick.
+func (b *Bus[T]) Update() {
+	b.start += uint64(len(b.previous))
+	clear(b.previous)
+	b.previous, b.current = b.current, b.previous[:0]
+}
+
+// Clear removes all events from the bus.
+func (b *Bus[T]) Clear() {
+	b.start += uint64(len(b.previous) + len(b.current))
+	clear(b.previous)
+	clear(b.current)
+	b.previous = b.previous[:0]
+	b.current = b.current[:0]
+}
+
+// Len returns the number of events held by the bus, for the current and the previous tick.
+func (b *Bus[T]) Len() int {
+	return len(b.previous) + len(b.current)
+}
+
+// end returns the absolute index after the last event.
+func (b *Bus[T]) end() uint64 {
+	return b.start + uint64(len(b.previous)+len(b.current))
+}
+
+// get returns the event at the given absolute index.
+// The index must be in the range of events held by the bus.
+func (b *Bus[T]) get(index uint64) *T {
+	idx := int(index - b.start)
+	if idx < len(b.previous) {
+		return &b.previous[idx]
+	}
+	return &b.current[idx-len(b.previous)]
+}
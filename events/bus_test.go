@@ -0,0 +1,196 @@
+package events_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/events"
+	"github.com/mlange-42/arche/generic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Collision struct {
+	A, B ecs.Entity
+}
+
+func TestBus(t *testing.T) {
+	w := ecs.NewWorld()
+	bus := events.NewBus[Collision](&w)
+
+	r1 := bus.Reader()
+	bus.Send(Collision{})
+	bus.Send(Collision{})
+	assert.Equal(t, 2, bus.Len())
+	assert.Equal(t, 2, r1.Len())
+
+	r2 := bus.Reader()
+	assert.Equal(t, 2, r2.Len())
+
+	cnt := 0
+	for r1.Next() {
+		assert.NotNil(t, r1.Get())
+		cnt++
+	}
+	assert.Equal(t, 2, cnt)
+	assert.Equal(t, 0, r1.Len())
+	assert.Nil(t, r1.Get())
+
+	bus.Update()
+	bus.Send(Collision{})
+	assert.Equal(t, 3, bus.Len())
+	assert.Equal(t, 1, r1.Len())
+	assert.Equal(t, 3, r2.Len())
+
+	bus.Update()
+	assert.Equal(t, 1, bus.Len())
+	assert.Equal(t, 1, r1.Len())
+	assert.Equal(t, 1, r2.Len())
+
+	cnt = 0
+	for r2.Next() {
+		cnt++
+	}
+	assert.Equal(t, 1, cnt)
+
+	bus.Update()
+	assert.Equal(t, 0, bus.Len())
+	assert.Equal(t, 0, r1.Len())
+	assert.False(t, r1.Next())
+
+	bus.Send(Collision{})
+	bus.Send(Collision{})
+	r1.Skip()
+	assert.Equal(t, 0, r1.Len())
+	assert.Equal(t, 2, r2.Len())
+
+	bus.Clear()
+	assert.Equal(t, 0, bus.Len())
+	assert.Equal(t, 0, r2.Len())
+	assert.False(t, r2.Next())
+}
+
+func TestBusOrder(t *testing.T) {
+	w := ecs.NewWorld()
+	bus := events.NewBus[int](&w)
+	reader := bus.Reader()
+
+	for i := 0; i < 5; i++ {
+		bus.Send(i)
+	}
+	bus.Update()
+	for i := 5; i < 10; i++ {
+		bus.Send(i)
+	}
+
+	values := []int{}
+	for reader.Next() {
+		values = append(values, *reader.Get())
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, values)
+}
+
+func TestBusSubscriber(t *testing.T) {
+	w := ecs.NewWorld()
+	bus := events.AddBus[Collision](&w)
+	assert.Equal(t, bus, events.GetBus[Collision](&w))
+	assert.Panics(t, func() { events.AddBus[Collision](&w) })
+
+	received := []Collision{}
+	sub := events.NewCallback(func(world *ecs.World, evt *Collision) {
+		assert.Equal(t, &w, world)
+		received = append(received, *evt)
+	})
+	bus.Subscribe(sub)
+
+	e1, e2 := w.NewEntity(), w.NewEntity()
+	bus.Send(Collision{A: e1, B: e2})
+	assert.Equal(t, []Collision{{A: e1, B: e2}}, received)
+
+	assert.True(t, bus.Unsubscribe(sub))
+	assert.False(t, bus.Unsubscribe(sub))
+
+	bus.Send(Collision{A: e2, B: e1})
+	assert.Equal(t, 1, len(received))
+}
+
+func TestBusSubscriberSend(t *testing.T) {
+	w := ecs.NewWorld()
+	bus := events.NewBus[Collision](&w)
+	e1, e2, e3 := w.NewEntity(), w.NewEntity(), w.NewEntity()
+
+	first := []Collision{}
+	bus.Subscribe(events.NewCallback(func(world *ecs.World, evt *Collision) {
+		first = append(first, *evt)
+		if evt.A == e1 {
+			// Re-uses the event buffer of the outer event.
+			bus.Update()
+			bus.Update()
+			bus.Send(Collision{A: e3, B: e3})
+		}
+	}))
+	second := []Collision{}
+	bus.Subscribe(events.NewCallback(func(world *ecs.World, evt *Collision) {
+		second = append(second, *evt)
+	}))
+
+	bus.Send(Collision{A: e1, B: e2})
+	assert.Equal(t, []Collision{{A: e1, B: e2}, {A: e3, B: e3}}, first)
+	assert.Equal(t, []Collision{{A: e3, B: e3}, {A: e1, B: e2}}, second)
+}
+
+func TestBusUnsubscribeDuringSend(t *testing.T) {
+	w := ecs.NewWorld()
+	bus := events.NewBus[Collision](&w)
+
+	calls := []string{}
+	var first *events.Callback[Collision]
+	first = events.NewCallback(func(world *ecs.World, evt *Collision) {
+		calls = append(calls, "first")
+		bus.Unsubscribe(first)
+	})
+	second := events.NewCallback(func(world *ecs.World, evt *Collision) {
+		calls = append(calls, "second")
+	})
+	third := events.NewCallback(func(world *ecs.World, evt *Collision) {
+		calls = append(calls, "third")
+	})
+	bus.Subscribe(first)
+	bus.Subscribe(second)
+	bus.Subscribe(third)
+
+	bus.Send(Collision{})
+	assert.Equal(t, []string{"first", "second", "third"}, calls)
+
+	calls = calls[:0]
+	bus.Send(Collision{})
+	assert.Equal(t, []string{"second", "third"}, calls)
+}
+
+func ExampleBus() {
+	world := ecs.NewWorld()
+
+	// Add a bus for collision events to the world's resources.
+	events.AddBus[Collision](&world)
+
+	// Systems can access the bus as a resource.
+	res := generic.NewResource[events.Bus[Collision]](&world)
+	bus := res.Get()
+
+	// A system that reads collisions creates its own reader, usually during initialization.
+	reader := bus.Reader()
+
+	// A system sends events.
+	a, b := world.NewEntity(), world.NewEntity()
+	bus.Send(Collision{A: a, B: b})
+
+	// Another system reads events.
+	for reader.Next() {
+		evt := reader.Get()
+		fmt.Println(evt.A, evt.B)
+	}
+
+	// Update the bus once per tick.
+	bus.Update()
+	// Output: {1 0} {2 0}
+}
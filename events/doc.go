@@ -0,0 +1,18 @@
+// Package events provides a typed event bus for user-defined events in Arche,
+// an Entity Component System (ECS) for Go.
+//
+// While [github.com/mlange-42/arche/ecs.Listener] is notified about ECS operations like entity creation,
+// the [Bus] allows systems to communicate via custom events like collisions or deaths.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// # Outline
+//
+//   - [Bus] is a double-buffered event queue for a single event type, intended to be used as a world resource.
+//   - [Reader] reads events from a [Bus], with an independent cursor per reader.
+//   - [Subscriber] is notified immediately about every event sent through a [Bus].
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package events
@@ -0,0 +1,57 @@
+package events
+
+// Reader reads events from a [Bus].
+//
+// Create one with [Bus.Reader].
+// Each reader has its own cursor, so multiple readers can read the same events independently.
+//
+// # Example
+//
+//	reader := bus.Reader()
+//	for reader.Next() {
+//		evt := reader.Get()
+//		// ...
+//	}
+type Reader[T any] struct {
+	bus    *Bus[T] // The bus to read from.
+	cursor uint64  // Absolute index of the next event to read.
+	event  *T      // The current event.
+}
+
+// Next proceeds to the next unread event.
+//
+// Returns false if there are no more unread events.
+// Events that were dropped by the bus before they were read are skipped.
+func (r *Reader[T]) Next() bool {
+	if r.cursor < r.bus.start {
+		r.cursor = r.bus.start
+	}
+	if r.cursor >= r.bus.end() {
+		r.event = nil
+		return false
+	}
+	r.event = r.bus.get(r.cursor)
+	r.cursor++
+	return true
+}
+
+// Get returns the event at the reader's current position.
+//
+// ⚠️ Important: The obtained pointer should not be stored persistently!
+func (r *Reader[T]) Get() *T {
+	return r.event
+}
+
+// Len returns the number of unread events.
+func (r *Reader[T]) Len() int {
+	if r.cursor < r.bus.start {
+		return r.bus.Len()
+	}
+	return int(r.bus.end() - r.cursor)
+}
+
+// Skip marks all events as read.
+func (r *Reader[T]) Skip() {
+	r.cursor = r.bus.end()
+	r.event = nil
+}
@@ -0,0 +1,29 @@
+package events
+
+import "github.com/mlange-42/arche/ecs"
+
+// Subscriber interface for immediate notification about events sent through a [Bus].
+//
+// Register a subscriber with [Bus.Subscribe].
+type Subscriber[T any] interface {
+	// Notify the subscriber about an event.
+	// The event pointer should not be stored persistently.
+	Notify(world *ecs.World, evt *T)
+}
+
+// Callback subscriber for events sent through a [Bus].
+//
+// Calls a function on every event.
+type Callback[T any] struct {
+	callback func(w *ecs.World, evt *T)
+}
+
+// NewCallback creates a new [Callback] subscriber.
+func NewCallback[T any](callback func(w *ecs.World, evt *T)) *Callback[T] {
+	return &Callback[T]{callback: callback}
+}
+
+// Notify the subscriber.
+func (c *Callback[T]) Notify(w *ecs.World, evt *T) {
+	c.callback(w, evt)
+}
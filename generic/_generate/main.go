@@ -30,6 +30,7 @@ type query struct {
 	IDAssign      string
 	IDAssign2     string
 	IDList        string
+	PredicateArgs string
 }
 
 func main() {
@@ -150,6 +151,22 @@ func generateQueries() {
 		panic(err)
 	}
 
+	iterText := bytes.Buffer{}
+
+	iterHeader, err := template.ParseFiles("./_generate/iter_header.go.txt")
+	if err != nil {
+		panic(err)
+	}
+	err = iterHeader.Execute(&iterText, struct{}{})
+	if err != nil {
+		panic(err)
+	}
+
+	iters, err := template.ParseFiles("./_generate/iter.go.txt")
+	if err != nil {
+		panic(err)
+	}
+
 	for i := 0; i <= maxIndex; i++ {
 		types := ""
 		returnTypes := ""
@@ -159,6 +176,8 @@ func generateQueries() {
 		idTypes := ""
 		idAssign := ""
 		variables := ""
+		arguments := ""
+		predicateArgs := ""
 		if i > 0 {
 			types = "[" + strings.Join(typeLetters[:i], ", ") + "]"
 			variables = strings.Join(variableLetters[:i], ", ")
@@ -169,34 +188,44 @@ func generateQueries() {
 			for j := 0; j < i; j++ {
 				returnAll += fmt.Sprintf("(*%s)(q.Query.Get(q.id%d))", typeLetters[j], j)
 				idAssign += fmt.Sprintf("	id%d: f.compiled.Ids[%d],\n", j, j)
+				arguments += fmt.Sprintf("%s *%s", variableLetters[j], typeLetters[j])
+				predicateArgs += fmt.Sprintf("(*%s)(p[%d])", typeLetters[j], j)
 				if j < i-1 {
 					returnAll += ",\n"
+					arguments += ", "
+					predicateArgs += ", "
 				}
 			}
 		} else {
 			include = ""
 		}
 		data := query{
-			Index:       i,
-			NumberStr:   numberStr[i],
-			Types:       types,
-			TypesReturn: returnTypes,
-			TypesFull:   fullTypes,
-			Variables:   variables,
-			ReturnAll:   returnAll,
-			Include:     include,
-			IDTypes:     idTypes,
-			IDAssign:    idAssign,
+			Index:         i,
+			NumberStr:     numberStr[i],
+			Types:         types,
+			TypesReturn:   returnTypes,
+			TypesFull:     fullTypes,
+			Variables:     variables,
+			ReturnAll:     returnAll,
+			Include:       include,
+			IDTypes:       idTypes,
+			IDAssign:      idAssign,
+			Arguments:     arguments,
+			PredicateArgs: predicateArgs,
 		}
 		err = filters.Execute(&text, data)
 		if err != nil {
 			panic(err)
 		}
-		if i == 0 {
-			continue
+		err = iters.Execute(&iterText, data)
+		if err != nil {
+			panic(err)
 		}
 	}
 	if err := os.WriteFile("query_generated.go", text.Bytes(), 0666); err != nil {
 		panic(err)
 	}
+	if err := os.WriteFile("iter_generated.go", iterText.Bytes(), 0666); err != nil {
+		panic(err)
+	}
 }
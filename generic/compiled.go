@@ -3,6 +3,7 @@ package generic
 import (
 	"fmt"
 	"reflect"
+	"unsafe"
 
 	"github.com/mlange-42/arche/ecs"
 )
@@ -11,17 +12,18 @@ var relationType = reflect.TypeOf((*ecs.Relation)(nil)).Elem()
 
 // compiledQuery is a helper for compiling a generic filter into a [ecs.Filter].
 type compiledQuery struct {
-	maskFilter     ecs.MaskFilter
-	relationFilter ecs.RelationFilter
-	cachedFilter   ecs.CachedFilter
-	filter         ecs.Filter
-	Ids            []ecs.ID
-	Relation       ecs.ID
-	Target         ecs.Entity
-	HasRelation    bool
-	compiled       bool
-	targetCompiled bool
-	locked         bool
+	maskFilter      ecs.MaskFilter
+	relationFilter  ecs.RelationFilter
+	cachedFilter    ecs.CachedFilter
+	predicateFilter ecs.PredicateFilter
+	filter          ecs.Filter
+	Ids             []ecs.ID
+	Relation        ecs.ID
+	Target          ecs.Entity
+	HasRelation     bool
+	compiled        bool
+	targetCompiled  bool
+	locked          bool
 }
 
 func newCompiledQuery() compiledQuery {
@@ -92,6 +94,44 @@ func (q *compiledQuery) Compile(w *ecs.World, include, optional, exclude []Comp,
 	q.compiled = true
 }
 
+// Predicate wraps the given filter into an [ecs.PredicateFilter] if a predicate is given.
+// The predicate receives the first count components of the filter.
+func (q *compiledQuery) Predicate(filter ecs.Filter, predicate func([]unsafe.Pointer) bool, count int) ecs.Filter {
+	if predicate == nil {
+		return filter
+	}
+	q.predicateFilter = ecs.NewPredicateFilter(filter, predicate, q.Ids[:count]...)
+	return &q.predicateFilter
+}
+
+// ReadFilter builds a filter for read-only queries, with an optional relation target
+// and an optional predicate.
+//
+// In contrast to [compiledQuery.Compile] and [compiledQuery.Predicate],
+// it does not modify the compiledQuery, so that it is safe for concurrent use.
+// Panics if the compiledQuery is not compiled yet.
+func (q *compiledQuery) ReadFilter(hasTarget bool, predicate func([]unsafe.Pointer) bool, count int, target ...ecs.Entity) ecs.Filter {
+	if !q.compiled {
+		panic("filter must be compiled before creating read-only queries. Call Filter or Register first")
+	}
+	filter := q.filter
+	if len(target) > 0 {
+		if q.locked {
+			panic("can't change relation target on a cached query")
+		}
+		if hasTarget {
+			panic("can't change relation target on a query with fixed target")
+		}
+		rf := ecs.NewRelationFilter(&q.maskFilter, target[0])
+		filter = &rf
+	}
+	if predicate != nil {
+		pf := ecs.NewPredicateFilter(filter, predicate, q.Ids[:count]...)
+		filter = &pf
+	}
+	return filter
+}
+
 // Reset sets the compiledQuery to not compiled.
 func (q *compiledQuery) Reset() {
 	q.compiled = false
@@ -7,6 +7,7 @@
 // # Outline
 //
 //   - [Filter0], [Filter1], etc. provide generic filters and query generation using [Filter0.Query] and friends.
+//     With Go 1.23 or later, filters also provide range-over-func iterators like [Filter1.Iter].
 //   - [Query0], [Query1], provide the usual [ecs.Query] functionality,
 //     as well as generic [Query1.Get], [Query1.Relation], etc.
 //   - [Map] provides generic access to a single component using world access, like [Map.Get] and [Map.Set].
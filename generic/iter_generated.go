@@ -0,0 +1,482 @@
+//go:build go1.23
+
+package generic
+
+// Code generated by go generate; DO NOT EDIT.
+
+import (
+	"iter"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Iter returns an iterator over the query's entities, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter0.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+//	for entity := range filter.Iter(&world) {
+//		// ...
+//	}
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter0.Query].
+func (f *Filter0) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq[ecs.Entity] {
+	return func(yield func(ecs.Entity) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity()) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter1.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+//	for entity, a := range filter.Iter(&world) {
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter1.Query].
+func (f *Filter1[A]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *A] {
+	return func(yield func(ecs.Entity, *A) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), query.Get()) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter2.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query2.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter2.Query].
+func (f *Filter2[A, B]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query2[A, B]] {
+	return func(yield func(ecs.Entity, *Query2[A, B]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter3.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query3.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter3.Query].
+func (f *Filter3[A, B, C]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query3[A, B, C]] {
+	return func(yield func(ecs.Entity, *Query3[A, B, C]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter4.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query4.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter4.Query].
+func (f *Filter4[A, B, C, D]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query4[A, B, C, D]] {
+	return func(yield func(ecs.Entity, *Query4[A, B, C, D]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter5.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query5.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter5.Query].
+func (f *Filter5[A, B, C, D, E]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query5[A, B, C, D, E]] {
+	return func(yield func(ecs.Entity, *Query5[A, B, C, D, E]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter6.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query6.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter6.Query].
+func (f *Filter6[A, B, C, D, E, F]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query6[A, B, C, D, E, F]] {
+	return func(yield func(ecs.Entity, *Query6[A, B, C, D, E, F]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter7.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query7.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f, g := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter7.Query].
+func (f *Filter7[A, B, C, D, E, F, G]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query7[A, B, C, D, E, F, G]] {
+	return func(yield func(ecs.Entity, *Query7[A, B, C, D, E, F, G]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter8.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query8.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f, g, h := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter8.Query].
+func (f *Filter8[A, B, C, D, E, F, G, H]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query8[A, B, C, D, E, F, G, H]] {
+	return func(yield func(ecs.Entity, *Query8[A, B, C, D, E, F, G, H]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter9.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query9.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f, g, h, i := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter9.Query].
+func (f *Filter9[A, B, C, D, E, F, G, H, I]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query9[A, B, C, D, E, F, G, H, I]] {
+	return func(yield func(ecs.Entity, *Query9[A, B, C, D, E, F, G, H, I]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter10.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query10.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f, g, h, i, j := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter10.Query].
+func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query10[A, B, C, D, E, F, G, H, I, J]] {
+	return func(yield func(ecs.Entity, *Query10[A, B, C, D, E, F, G, H, I, J]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter11.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query11.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f, g, h, i, j, k := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter11.Query].
+func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query11[A, B, C, D, E, F, G, H, I, J, K]] {
+	return func(yield func(ecs.Entity, *Query11[A, B, C, D, E, F, G, H, I, J, K]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
+
+// Iter returns an iterator over the query's entities and components, with an optional relation target.
+//
+// The query is created when iteration starts, and thus locks the world during iteration.
+// In contrast to manual iteration using [Filter12.Query], the query is closed automatically
+// when breaking out of the loop early, or when the loop body panics.
+//
+// The iterator yields the current entity and the query.
+// Use [Query12.Get] to access the components:
+//
+//	for entity, query := range filter.Iter(&world) {
+//		a, b, c, d, e, f, g, h, i, j, k, l := query.Get()
+//		// ...
+//	}
+//
+// ⚠️ Important: The obtained pointers should not be stored persistently!
+//
+// Requires Go 1.23 or later.
+// See [ecs.World.Entities] for performance considerations.
+// For the relation target, see [Filter12.Query].
+func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) Iter(w *ecs.World, target ...ecs.Entity) iter.Seq2[ecs.Entity, *Query12[A, B, C, D, E, F, G, H, I, J, K, L]] {
+	return func(yield func(ecs.Entity, *Query12[A, B, C, D, E, F, G, H, I, J, K, L]) bool) {
+		query := f.Query(w, target...)
+		open := true
+		defer func() {
+			if open {
+				query.Close()
+			}
+		}()
+		for query.Next() {
+			if !yield(query.Entity(), &query) {
+				return
+			}
+		}
+		open = false
+	}
+}
@@ -0,0 +1,74 @@
+//go:build go1.23
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterIter(t *testing.T) {
+	w := ecs.NewWorld()
+
+	mapper := NewMap2[Position, Velocity](&w)
+	mapper.NewBatch(10)
+
+	filter0 := NewFilter0().With(T[Position]())
+	cnt := 0
+	for e := range filter0.Iter(&w) {
+		assert.True(t, w.Has(e, mapper.id0))
+		cnt++
+	}
+	assert.Equal(t, 10, cnt)
+	assert.False(t, w.IsLocked())
+
+	filter1 := NewFilter1[Position]()
+	cnt = 0
+	for e, pos := range filter1.Iter(&w) {
+		pos.X = cnt
+		p, _ := mapper.Get(e)
+		assert.Equal(t, cnt, p.X)
+		cnt++
+	}
+	assert.Equal(t, 10, cnt)
+	assert.False(t, w.IsLocked())
+
+	filter2 := NewFilter2[Position, Velocity]()
+	cnt = 0
+	for _, query := range filter2.Iter(&w) {
+		pos, vel := query.Get()
+		vel.X = pos.X
+		cnt++
+		if cnt == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, cnt)
+	assert.False(t, w.IsLocked())
+
+	assert.Panics(t, func() {
+		for range filter2.Iter(&w) {
+			panic("test")
+		}
+	})
+	assert.False(t, w.IsLocked())
+}
+
+func TestFilterIterRelation(t *testing.T) {
+	w := ecs.NewWorld()
+
+	parent := w.NewEntity()
+	mapper := NewMap2[Position, testRelationA](&w, T[testRelationA]())
+	mapper.NewBatch(5, parent)
+	mapper.NewBatch(5)
+
+	filter := NewFilter1[Position]().With(T[testRelationA]()).WithRelation(T[testRelationA]())
+	cnt := 0
+	for range filter.Iter(&w, parent) {
+		cnt++
+	}
+	assert.Equal(t, 5, cnt)
+	assert.False(t, w.IsLocked())
+}
@@ -68,6 +68,8 @@ func (m *Map[T]) HasUnchecked(entity ecs.Entity) bool {
 }
 
 // Set overwrites the component for the given entity.
+// Calls the component's OnSet hook, if any (see [ecs.RegisterHooks]).
+// Saves the entity in the active transaction, if any (see [ecs.Tx]).
 //
 // Panics if the entity does not have a component of that type.
 //
@@ -77,7 +79,9 @@ func (m *Map[T]) Set(entity ecs.Entity, comp *T) *T {
 	if p == nil {
 		panic("can't copy component into entity that has no such component type")
 	}
+	m.world.Track(entity)
 	*p = *comp
+	m.world.NotifySetUnchecked(entity, m.id)
 	return p
 }
 
@@ -41,6 +41,79 @@ func TestGenericMap(t *testing.T) {
 	assert.PanicsWithValue(t, "can't get component of a dead entity", func() { get.Get(e0) })
 }
 
+func TestGenericMapHooks(t *testing.T) {
+	w := ecs.NewWorld()
+
+	calls := []string{}
+	ecs.RegisterHooks[Position](&w,
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { calls = append(calls, "add") },
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { calls = append(calls, "remove") },
+		func(w *ecs.World, entity ecs.Entity, pos *Position) { calls = append(calls, "set") },
+	)
+
+	posMap := NewMap[Position](&w)
+	map2 := NewMap2[Position, Velocity](&w)
+
+	e := map2.NewWith(&Position{}, &Velocity{})
+	posMap.Set(e, &Position{X: 1})
+	map2.Remove(e)
+	map2.Assign(e, &Position{}, &Velocity{})
+	map2.NewBatch(2)
+	map2.RemoveEntities(true)
+
+	assert.Equal(t, []string{"add", "set", "remove", "add", "add", "add", "remove", "remove", "remove"}, calls)
+}
+
+func TestGenericMapDefaults(t *testing.T) {
+	w := ecs.NewWorld()
+	ecs.RegisterDefault(&w, Position{X: 1, Y: 2})
+
+	posMap := NewMap[Position](&w)
+	map1 := NewMap1[Position](&w)
+	map2 := NewMap2[Position, Velocity](&w)
+	velMap := NewMap1[Velocity](&w)
+
+	e1 := map2.New()
+	pos, vel := map2.Get(e1)
+	assert.Equal(t, Position{X: 1, Y: 2}, *pos)
+	assert.Equal(t, Velocity{}, *vel)
+
+	e2 := map1.NewWith(&Position{X: 5})
+	assert.Equal(t, Position{X: 5}, *posMap.Get(e2))
+
+	e3 := velMap.New()
+	map1.Add(e3)
+	assert.Equal(t, Position{X: 1, Y: 2}, *posMap.Get(e3))
+
+	query := map2.NewBatchQ(3)
+	for query.Next() {
+		pos, _ := query.Get()
+		assert.Equal(t, Position{X: 1, Y: 2}, *pos)
+	}
+}
+
+func TestGenericMapTx(t *testing.T) {
+	w := ecs.NewWorld()
+
+	posMap := NewMap[Position](&w)
+	map2 := NewMap2[Position, Velocity](&w)
+
+	e1 := map2.NewWith(&Position{X: 1}, &Velocity{X: 1})
+	e2 := w.NewEntity()
+
+	tx := w.Begin()
+	posMap.Set(e1, &Position{X: 2})
+	map2.Assign(e2, &Position{X: 3}, &Velocity{X: 3})
+	e3 := map2.NewWith(&Position{X: 4}, &Velocity{X: 4})
+	assert.Equal(t, 2, posMap.Get(e1).X)
+	tx.Rollback()
+
+	assert.Equal(t, 1, posMap.Get(e1).X)
+	assert.False(t, posMap.Has(e2))
+	assert.False(t, w.Alive(e3))
+	assert.NoError(t, w.Validate())
+}
+
 func TestGenericMapRelations(t *testing.T) {
 	w := ecs.NewWorld()
 	get := NewMap[testRelationA](&w)
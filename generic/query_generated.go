@@ -3,6 +3,8 @@ package generic
 // Code generated by go generate; DO NOT EDIT.
 
 import (
+	"unsafe"
+
 	"github.com/mlange-42/arche/ecs"
 )
 
@@ -115,7 +117,7 @@ func (f *Filter0) Filter(w *ecs.World, target ...ecs.Entity) ecs.Filter {
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 0)
 }
 
 // Query builds a [Query0] query for iteration, with an optional relation target.
@@ -135,6 +137,25 @@ func (f *Filter0) Query(w *ecs.World, target ...ecs.Entity) Query0 {
 	}
 }
 
+// QueryRead builds a read-only [Query0] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter0.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter0.Filter] or [Filter0.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter0.Query].
+func (f *Filter0) QueryRead(w *ecs.World, target ...ecs.Entity) Query0 {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 0, target...)
+	return Query0{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -266,6 +287,24 @@ func (f *Filter1[A]) Exclusive() *Filter1[A] {
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter1[A]) Where(fn func(a *A) bool) *Filter1[A] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -310,7 +349,7 @@ func (f *Filter1[A]) Filter(w *ecs.World, target ...ecs.Entity) ecs.Filter {
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 1)
 }
 
 // Query builds a [Query1] query for iteration, with an optional relation target.
@@ -331,6 +370,26 @@ func (f *Filter1[A]) Query(w *ecs.World, target ...ecs.Entity) Query1[A] {
 	}
 }
 
+// QueryRead builds a read-only [Query1] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter1.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter1.Filter] or [Filter1.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter1.Query].
+func (f *Filter1[A]) QueryRead(w *ecs.World, target ...ecs.Entity) Query1[A] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 1, target...)
+	return Query1[A]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -473,6 +532,24 @@ func (f *Filter2[A, B]) Exclusive() *Filter2[A, B] {
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter2[A, B]) Where(fn func(a *A, b *B) bool) *Filter2[A, B] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -517,7 +594,7 @@ func (f *Filter2[A, B]) Filter(w *ecs.World, target ...ecs.Entity) ecs.Filter {
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 2)
 }
 
 // Query builds a [Query2] query for iteration, with an optional relation target.
@@ -539,6 +616,27 @@ func (f *Filter2[A, B]) Query(w *ecs.World, target ...ecs.Entity) Query2[A, B] {
 	}
 }
 
+// QueryRead builds a read-only [Query2] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter2.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter2.Filter] or [Filter2.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter2.Query].
+func (f *Filter2[A, B]) QueryRead(w *ecs.World, target ...ecs.Entity) Query2[A, B] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 2, target...)
+	return Query2[A, B]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -684,6 +782,24 @@ func (f *Filter3[A, B, C]) Exclusive() *Filter3[A, B, C] {
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter3[A, B, C]) Where(fn func(a *A, b *B, c *C) bool) *Filter3[A, B, C] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -728,7 +844,7 @@ func (f *Filter3[A, B, C]) Filter(w *ecs.World, target ...ecs.Entity) ecs.Filter
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 3)
 }
 
 // Query builds a [Query3] query for iteration, with an optional relation target.
@@ -751,6 +867,28 @@ func (f *Filter3[A, B, C]) Query(w *ecs.World, target ...ecs.Entity) Query3[A, B
 	}
 }
 
+// QueryRead builds a read-only [Query3] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter3.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter3.Filter] or [Filter3.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter3.Query].
+func (f *Filter3[A, B, C]) QueryRead(w *ecs.World, target ...ecs.Entity) Query3[A, B, C] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 3, target...)
+	return Query3[A, B, C]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -899,6 +1037,24 @@ func (f *Filter4[A, B, C, D]) Exclusive() *Filter4[A, B, C, D] {
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter4[A, B, C, D]) Where(fn func(a *A, b *B, c *C, d *D) bool) *Filter4[A, B, C, D] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -943,7 +1099,7 @@ func (f *Filter4[A, B, C, D]) Filter(w *ecs.World, target ...ecs.Entity) ecs.Fil
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 4)
 }
 
 // Query builds a [Query4] query for iteration, with an optional relation target.
@@ -967,6 +1123,29 @@ func (f *Filter4[A, B, C, D]) Query(w *ecs.World, target ...ecs.Entity) Query4[A
 	}
 }
 
+// QueryRead builds a read-only [Query4] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter4.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter4.Filter] or [Filter4.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter4.Query].
+func (f *Filter4[A, B, C, D]) QueryRead(w *ecs.World, target ...ecs.Entity) Query4[A, B, C, D] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 4, target...)
+	return Query4[A, B, C, D]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -1118,6 +1297,24 @@ func (f *Filter5[A, B, C, D, E]) Exclusive() *Filter5[A, B, C, D, E] {
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter5[A, B, C, D, E]) Where(fn func(a *A, b *B, c *C, d *D, e *E) bool) *Filter5[A, B, C, D, E] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -1162,7 +1359,7 @@ func (f *Filter5[A, B, C, D, E]) Filter(w *ecs.World, target ...ecs.Entity) ecs.
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 5)
 }
 
 // Query builds a [Query5] query for iteration, with an optional relation target.
@@ -1187,6 +1384,30 @@ func (f *Filter5[A, B, C, D, E]) Query(w *ecs.World, target ...ecs.Entity) Query
 	}
 }
 
+// QueryRead builds a read-only [Query5] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter5.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter5.Filter] or [Filter5.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter5.Query].
+func (f *Filter5[A, B, C, D, E]) QueryRead(w *ecs.World, target ...ecs.Entity) Query5[A, B, C, D, E] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 5, target...)
+	return Query5[A, B, C, D, E]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -1341,6 +1562,24 @@ func (f *Filter6[A, B, C, D, E, F]) Exclusive() *Filter6[A, B, C, D, E, F] {
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter6[A, B, C, D, E, F]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F) bool) *Filter6[A, B, C, D, E, F] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -1385,7 +1624,7 @@ func (f *Filter6[A, B, C, D, E, F]) Filter(w *ecs.World, target ...ecs.Entity) e
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 6)
 }
 
 // Query builds a [Query6] query for iteration, with an optional relation target.
@@ -1411,6 +1650,31 @@ func (f *Filter6[A, B, C, D, E, F]) Query(w *ecs.World, target ...ecs.Entity) Qu
 	}
 }
 
+// QueryRead builds a read-only [Query6] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter6.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter6.Filter] or [Filter6.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter6.Query].
+func (f *Filter6[A, B, C, D, E, F]) QueryRead(w *ecs.World, target ...ecs.Entity) Query6[A, B, C, D, E, F] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 6, target...)
+	return Query6[A, B, C, D, E, F]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -1568,6 +1832,24 @@ func (f *Filter7[A, B, C, D, E, F, G]) Exclusive() *Filter7[A, B, C, D, E, F, G]
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter7[A, B, C, D, E, F, G]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G) bool) *Filter7[A, B, C, D, E, F, G] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]), (*G)(p[6]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -1612,7 +1894,7 @@ func (f *Filter7[A, B, C, D, E, F, G]) Filter(w *ecs.World, target ...ecs.Entity
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 7)
 }
 
 // Query builds a [Query7] query for iteration, with an optional relation target.
@@ -1639,6 +1921,32 @@ func (f *Filter7[A, B, C, D, E, F, G]) Query(w *ecs.World, target ...ecs.Entity)
 	}
 }
 
+// QueryRead builds a read-only [Query7] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter7.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter7.Filter] or [Filter7.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter7.Query].
+func (f *Filter7[A, B, C, D, E, F, G]) QueryRead(w *ecs.World, target ...ecs.Entity) Query7[A, B, C, D, E, F, G] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 7, target...)
+	return Query7[A, B, C, D, E, F, G]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+		id6:         f.compiled.Ids[6],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -1799,6 +2107,24 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Exclusive() *Filter8[A, B, C, D, E, F,
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter8[A, B, C, D, E, F, G, H]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H) bool) *Filter8[A, B, C, D, E, F, G, H] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]), (*G)(p[6]), (*H)(p[7]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -1843,7 +2169,7 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Filter(w *ecs.World, target ...ecs.Ent
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 8)
 }
 
 // Query builds a [Query8] query for iteration, with an optional relation target.
@@ -1871,6 +2197,33 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Query(w *ecs.World, target ...ecs.Enti
 	}
 }
 
+// QueryRead builds a read-only [Query8] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter8.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter8.Filter] or [Filter8.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter8.Query].
+func (f *Filter8[A, B, C, D, E, F, G, H]) QueryRead(w *ecs.World, target ...ecs.Entity) Query8[A, B, C, D, E, F, G, H] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 8, target...)
+	return Query8[A, B, C, D, E, F, G, H]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+		id6:         f.compiled.Ids[6],
+		id7:         f.compiled.Ids[7],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -2034,6 +2387,24 @@ func (f *Filter9[A, B, C, D, E, F, G, H, I]) Exclusive() *Filter9[A, B, C, D, E,
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter9[A, B, C, D, E, F, G, H, I]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I) bool) *Filter9[A, B, C, D, E, F, G, H, I] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]), (*G)(p[6]), (*H)(p[7]), (*I)(p[8]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -2078,7 +2449,7 @@ func (f *Filter9[A, B, C, D, E, F, G, H, I]) Filter(w *ecs.World, target ...ecs.
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 9)
 }
 
 // Query builds a [Query9] query for iteration, with an optional relation target.
@@ -2107,6 +2478,34 @@ func (f *Filter9[A, B, C, D, E, F, G, H, I]) Query(w *ecs.World, target ...ecs.E
 	}
 }
 
+// QueryRead builds a read-only [Query9] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter9.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter9.Filter] or [Filter9.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter9.Query].
+func (f *Filter9[A, B, C, D, E, F, G, H, I]) QueryRead(w *ecs.World, target ...ecs.Entity) Query9[A, B, C, D, E, F, G, H, I] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 9, target...)
+	return Query9[A, B, C, D, E, F, G, H, I]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+		id6:         f.compiled.Ids[6],
+		id7:         f.compiled.Ids[7],
+		id8:         f.compiled.Ids[8],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -2273,6 +2672,24 @@ func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) Exclusive() *Filter10[A, B, C,
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J) bool) *Filter10[A, B, C, D, E, F, G, H, I, J] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]), (*G)(p[6]), (*H)(p[7]), (*I)(p[8]), (*J)(p[9]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -2317,7 +2734,7 @@ func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) Filter(w *ecs.World, target ...
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 10)
 }
 
 // Query builds a [Query10] query for iteration, with an optional relation target.
@@ -2347,6 +2764,35 @@ func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) Query(w *ecs.World, target ...e
 	}
 }
 
+// QueryRead builds a read-only [Query10] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter10.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter10.Filter] or [Filter10.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter10.Query].
+func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) QueryRead(w *ecs.World, target ...ecs.Entity) Query10[A, B, C, D, E, F, G, H, I, J] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 10, target...)
+	return Query10[A, B, C, D, E, F, G, H, I, J]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+		id6:         f.compiled.Ids[6],
+		id7:         f.compiled.Ids[7],
+		id8:         f.compiled.Ids[8],
+		id9:         f.compiled.Ids[9],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -2516,6 +2962,24 @@ func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) Exclusive() *Filter11[A, B,
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K) bool) *Filter11[A, B, C, D, E, F, G, H, I, J, K] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]), (*G)(p[6]), (*H)(p[7]), (*I)(p[8]), (*J)(p[9]), (*K)(p[10]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -2560,7 +3024,7 @@ func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) Filter(w *ecs.World, target
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 11)
 }
 
 // Query builds a [Query11] query for iteration, with an optional relation target.
@@ -2591,6 +3055,36 @@ func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) Query(w *ecs.World, target .
 	}
 }
 
+// QueryRead builds a read-only [Query11] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter11.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter11.Filter] or [Filter11.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter11.Query].
+func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) QueryRead(w *ecs.World, target ...ecs.Entity) Query11[A, B, C, D, E, F, G, H, I, J, K] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 11, target...)
+	return Query11[A, B, C, D, E, F, G, H, I, J, K]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+		id6:         f.compiled.Ids[6],
+		id7:         f.compiled.Ids[7],
+		id8:         f.compiled.Ids[8],
+		id9:         f.compiled.Ids[9],
+		id10:        f.compiled.Ids[10],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
@@ -2763,6 +3257,24 @@ func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) Exclusive() *Filter12[A,
 	return f
 }
 
+// Where sets a predicate on the values of the filter's components.
+// Entities for which the predicate returns false are skipped during query iteration.
+//
+// Pointers for optional components may be nil.
+// The pointers passed to the predicate should not be stored persistently!
+//
+// Filters with a predicate can't be used for batch operations.
+// For details and performance implications, see [ecs.PredicateFilter].
+func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) Where(fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K, l *L) bool) *Filter12[A, B, C, D, E, F, G, H, I, J, K, L] {
+	if f.compiled.locked {
+		panic("can't modify a registered filter")
+	}
+	f.predicate = func(p []unsafe.Pointer) bool {
+		return fn((*A)(p[0]), (*B)(p[1]), (*C)(p[2]), (*D)(p[3]), (*E)(p[4]), (*F)(p[5]), (*G)(p[6]), (*H)(p[7]), (*I)(p[8]), (*J)(p[9]), (*K)(p[10]), (*L)(p[11]))
+	}
+	return f
+}
+
 // WithRelation sets the filter's [ecs.Relation] component and optionally
 // restricts the query to entities that have the given relation target.
 //
@@ -2807,7 +3319,7 @@ func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) Filter(w *ecs.World, targ
 		filter = &f.compiled.relationFilter
 	}
 
-	return filter
+	return f.compiled.Predicate(filter, f.predicate, 12)
 }
 
 // Query builds a [Query12] query for iteration, with an optional relation target.
@@ -2839,6 +3351,37 @@ func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) Query(w *ecs.World, targe
 	}
 }
 
+// QueryRead builds a read-only [Query12] query for iteration, with an optional relation target.
+//
+// In contrast to [Filter12.Query], read-only queries can be created and iterated concurrently
+// from multiple goroutines. See [ecs.World.QueryRead] for details.
+//
+// QueryRead does not modify the filter. Therefore, the filter must be compiled before,
+// using [Filter12.Filter] or [Filter12.Register].
+// It must not be modified while read-only queries are created from it.
+//
+// Panics if the filter is not compiled, and for the same reasons as [Filter12.Query].
+func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) QueryRead(w *ecs.World, target ...ecs.Entity) Query12[A, B, C, D, E, F, G, H, I, J, K, L] {
+	filter := f.compiled.ReadFilter(f.hasTarget, f.predicate, 12, target...)
+	return Query12[A, B, C, D, E, F, G, H, I, J, K, L]{
+		Query:       w.QueryRead(filter),
+		relation:    f.compiled.Relation,
+		hasRelation: f.compiled.HasRelation,
+		id0:         f.compiled.Ids[0],
+		id1:         f.compiled.Ids[1],
+		id2:         f.compiled.Ids[2],
+		id3:         f.compiled.Ids[3],
+		id4:         f.compiled.Ids[4],
+		id5:         f.compiled.Ids[5],
+		id6:         f.compiled.Ids[6],
+		id7:         f.compiled.Ids[7],
+		id8:         f.compiled.Ids[8],
+		id9:         f.compiled.Ids[9],
+		id10:        f.compiled.Ids[10],
+		id11:        f.compiled.Ids[11],
+	}
+}
+
 // Register the filter for caching.
 //
 // See [ecs.Cache] for details on filter caching.
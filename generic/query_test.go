@@ -1,6 +1,7 @@
 package generic
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/mlange-42/arche/ecs"
@@ -1852,3 +1853,106 @@ func BenchmarkQueryCreate(b *testing.B) {
 		query.Close()
 	}
 }
+
+func TestQueryWhere(t *testing.T) {
+	w := ecs.NewWorld()
+
+	posMap := NewMap2[Position, Velocity](&w)
+	posOnly := NewMap1[Position](&w)
+
+	for i := 0; i < 10; i++ {
+		e := posOnly.New()
+		posOnly.Get(e).X = i
+		e = posMap.New()
+		pos, vel := posMap.Get(e)
+		pos.X = i
+		vel.X = -i
+	}
+
+	filter := NewFilter2[Position, Velocity]().
+		Optional(T[Velocity]()).
+		Where(func(pos *Position, vel *Velocity) bool {
+			return pos.X >= 5 && (vel == nil || vel.X < -7)
+		})
+
+	query := filter.Query(&w)
+	assert.Equal(t, 7, query.Count())
+	cnt := 0
+	for query.Next() {
+		pos, vel := query.Get()
+		assert.GreaterOrEqual(t, pos.X, 5)
+		if vel != nil {
+			assert.Less(t, vel.X, -7)
+		}
+		cnt++
+	}
+	assert.Equal(t, 7, cnt)
+
+	filter.Register(&w)
+	query = filter.Query(&w)
+	assert.Equal(t, 7, query.Count())
+	query.Close()
+
+	assert.Panics(t, func() { w.Batch().RemoveEntities(filter.Filter(&w)) })
+	assert.PanicsWithValue(t, "can't modify a registered filter",
+		func() { filter.Where(func(pos *Position, vel *Velocity) bool { return true }) })
+	filter.Unregister(&w)
+
+	filter1 := NewFilter1[Position]().Where(func(pos *Position) bool { return pos.X == 3 })
+	query1 := filter1.Query(&w)
+	assert.Equal(t, 2, query1.Count())
+	for query1.Next() {
+		assert.Equal(t, 3, query1.Get().X)
+	}
+	assert.False(t, w.IsLocked())
+}
+
+func TestQueryRead(t *testing.T) {
+	w := ecs.NewWorld()
+
+	posMap := NewMap2[Position, testRelationA](&w, T[testRelationA]())
+	parent := w.NewEntity()
+	for i := 0; i < 10; i++ {
+		e := posMap.New(parent)
+		pos, _ := posMap.Get(e)
+		pos.X = i
+	}
+
+	filter := NewFilter2[Position, testRelationA]().WithRelation(T[testRelationA]())
+	assert.PanicsWithValue(t, "filter must be compiled before creating read-only queries. Call Filter or Register first",
+		func() { filter.QueryRead(&w) })
+	_ = filter.Filter(&w)
+
+	query := filter.QueryRead(&w, parent)
+	assert.True(t, w.IsLocked())
+	assert.Equal(t, 10, query.Count())
+	for query.Next() {
+		assert.Equal(t, parent, query.Relation())
+	}
+	assert.False(t, w.IsLocked())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			query := filter.QueryRead(&w)
+			sum := 0
+			for query.Next() {
+				pos, _ := query.Get()
+				sum += pos.X
+			}
+			assert.Equal(t, 45, sum)
+		}()
+	}
+	wg.Wait()
+	assert.False(t, w.IsLocked())
+
+	where := NewFilter1[Position]().Where(func(pos *Position) bool { return pos.X >= 5 })
+	where.Register(&w)
+	query1 := where.QueryRead(&w)
+	assert.Equal(t, 5, query1.Count())
+	query1.Close()
+	assert.PanicsWithValue(t, "can't change relation target on a cached query", func() { where.QueryRead(&w, parent) })
+	assert.False(t, w.IsLocked())
+}
@@ -46,6 +46,17 @@ func (g *Resource[T]) Remove() {
 	g.world.Resources().Remove(g.id)
 }
 
+// Set replaces the resource of the given type, and notifies the world's [ecs.ResourceListener].
+//
+// Can also be called with the current resource, to notify about in-place modifications.
+//
+// Panics if there is no resource of the given type.
+//
+// See also [ecs.Resources.Set].
+func (g *Resource[T]) Set(res *T) {
+	g.world.Resources().Set(g.id, res)
+}
+
 // Get gets the resource of the given type.
 //
 // Returns nil if there is no such resource.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,10 +22,43 @@ func TestGenericResource(t *testing.T) {
 
 	assert.Equal(t, testStruct0{100}, *res)
 
+	get.Set(&testStruct0{50})
+	assert.Equal(t, testStruct0{50}, *get.Get())
+
 	get.Remove()
 	assert.False(t, get.Has())
+	assert.Panics(t, func() { get.Set(&testStruct0{30}) })
 }
 
+func TestGenericResourceListener(t *testing.T) {
+	w := ecs.NewWorld()
+	get := NewResource[testStruct0](&w)
+
+	events := []ecs.ResourceEvent{}
+	ls := resourceListener{callback: func(w *ecs.World, e ecs.ResourceEvent) { events = append(events, e) }}
+	w.SetResourceListener(&ls)
+
+	get.Add(&testStruct0{100})
+	get.Set(&testStruct0{50})
+	get.Remove()
+
+	assert.Equal(t, []ecs.ResourceEvent{
+		{Resource: get.ID(), EventTypes: event.ResourceAdded},
+		{Resource: get.ID(), EventTypes: event.ResourceChanged},
+		{Resource: get.ID(), EventTypes: event.ResourceRemoved},
+	}, events)
+}
+
+type resourceListener struct {
+	callback func(w *ecs.World, e ecs.ResourceEvent)
+}
+
+func (l *resourceListener) Notify(w *ecs.World, e ecs.ResourceEvent) { l.callback(w, e) }
+
+func (l *resourceListener) Subscriptions() event.ResourceSubscription { return event.Resources }
+
+func (l *resourceListener) Resources() *ecs.Mask { return nil }
+
 func ExampleResource() {
 	world := ecs.NewWorld()
 	myRes := Position{}
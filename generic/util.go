@@ -2,6 +2,7 @@ package generic
 
 import (
 	"reflect"
+	"unsafe"
 
 	"github.com/mlange-42/arche/ecs"
 )
@@ -15,6 +16,7 @@ type filter struct {
 	targetType Comp
 	target     ecs.Entity
 	hasTarget  bool
+	predicate  func(comps []unsafe.Pointer) bool
 	compiled   compiledQuery
 }
 
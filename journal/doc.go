@@ -0,0 +1,15 @@
+// Package journal provides recording and deterministic replay of structural world operations
+// for Arche, an Entity Component System (ECS) for Go.
+//
+// A [Journal] is an [github.com/mlange-42/arche/ecs.Listener] that records entity creation and removal,
+// component addition and removal, and relation and target changes into an append-only log.
+// The log can be replayed onto a fresh [github.com/mlange-42/arche/ecs.World] to reproduce the exact
+// entity state, including entity IDs and generations. This is useful for debugging divergent runs.
+// Component values are not recorded.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package journal
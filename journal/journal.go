@@ -0,0 +1,207 @@
+package journal
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// record of a single structural operation.
+type record struct {
+	Entity      ecs.Entity         // The changed entity.
+	Target      ecs.Entity         // The new relation target, if the target changed.
+	Relation    ecs.ID             // The new relation component, if the target changed.
+	AddedStart  uint32             // Start index of added component IDs.
+	AddedEnd    uint32             // End index of added component IDs.
+	RemovedEnd  uint32             // End index of removed component IDs. They start at AddedEnd.
+	EventTypes  event.Subscription // Event types of the operation.
+	HasRelation bool               // Whether there is a relation target change.
+}
+
+// initialEntity is the state of an entity at the start of recording.
+type initialEntity struct {
+	Entity   ecs.Entity // The entity.
+	Target   ecs.Entity // The relation target.
+	Relation ecs.ID     // The relation component.
+	Start    uint32     // Start index of component IDs.
+	End      uint32     // End index of component IDs.
+	HasRel   bool       // Whether the entity has a relation.
+}
+
+// Journal records structural world operations, and replays them onto a fresh world.
+//
+// Journal is an [ecs.Listener] and must be registered with [ecs.World.SetListener]
+// (or as a sub-listener of [github.com/mlange-42/arche/listener.Dispatch]) right after its creation.
+//
+// Recorded are entity creation and removal, component addition and removal, and relation and target changes.
+// Component values and resources are not recorded.
+// This includes values written through the generic API, like [github.com/mlange-42/arche/generic.Map.Set].
+// Operations that do not emit events, like [ecs.World.Reset], are not recorded either.
+//
+// Events deferred by transactions or by [github.com/mlange-42/arche/listener.Buffered] are recorded when they are delivered.
+//
+// The journal can be split into ticks with [Journal.Tick], e.g. at the end of each model step.
+// [Journal.ReplayTo] then allows to reproduce the state at a tick boundary.
+type Journal struct {
+	components []ecs.CompInfo  // Registered component types at the start of recording.
+	dump       ecs.EntityDump  // Entity state at the start of recording.
+	initial    []initialEntity // Component composition at the start of recording.
+	records    []record        // Recorded operations.
+	ids        []ecs.ID        // Backing memory for component IDs of records and initial entities.
+	ticks      []int           // Record index at the end of each tick.
+}
+
+// New creates a new [Journal], recording the initial state of the given world.
+//
+// The initial state comprises the registered component types, the entity state as obtained by
+// [ecs.World.DumpEntities], and the components and relation targets of all alive entities.
+//
+// Relation targets that are already dead at the start of recording can't be restored,
+// and are replayed as the zero entity.
+func New(world *ecs.World) Journal {
+	j := Journal{}
+	j.recordInitial(world)
+	return j
+}
+
+// Notify the listener. Records the operation of the event.
+func (j *Journal) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	rec := record{
+		Entity:     evt.Entity,
+		EventTypes: evt.EventTypes,
+	}
+	rec.AddedStart = uint32(len(j.ids))
+	j.ids = append(j.ids, evt.AddedIDs...)
+	rec.AddedEnd = uint32(len(j.ids))
+	j.ids = append(j.ids, evt.RemovedIDs...)
+	rec.RemovedEnd = uint32(len(j.ids))
+
+	if !evt.Contains(event.EntityRemoved) && evt.Contains(event.TargetChanged) && evt.NewRelation != nil {
+		rec.HasRelation = true
+		rec.Relation = *evt.NewRelation
+		rec.Target = evt.NewTarget
+	}
+	j.records = append(j.records, rec)
+}
+
+// Subscriptions of the listener. Subscribes to all event types.
+func (j *Journal) Subscriptions() event.Subscription {
+	return event.All
+}
+
+// Components the listener subscribes to. Subscribes to all components.
+func (j *Journal) Components() *ecs.Mask {
+	return nil
+}
+
+// Tick marks the end of a tick, e.g. of a model step.
+func (j *Journal) Tick() {
+	j.ticks = append(j.ticks, len(j.records))
+}
+
+// Ticks returns the number of ticks recorded so far.
+func (j *Journal) Ticks() int {
+	return len(j.ticks)
+}
+
+// Len returns the number of recorded operations.
+func (j *Journal) Len() int {
+	return len(j.records)
+}
+
+// Replay replays the initial state and all recorded operations onto the given world.
+//
+// The world must be fresh or reset, and must not have any other component types registered
+// than the ones registered in the recorded world at the start of recording.
+// Component values are zeroed, as they are not recorded.
+//
+// Panics if the world is not fresh, or if the replay diverges from the recording.
+func (j *Journal) Replay(world *ecs.World) {
+	j.replay(world, len(j.records))
+}
+
+// ReplayTo replays the initial state and all operations up to the end of the given tick onto the given world.
+// Tick 0 denotes the initial state.
+//
+// See [Journal.Replay] for details. Panics if the tick is out of range.
+func (j *Journal) ReplayTo(world *ecs.World, tick int) {
+	if tick < 0 || tick > len(j.ticks) {
+		panic(fmt.Sprintf("tick %d out of range [0, %d]", tick, len(j.ticks)))
+	}
+	end := 0
+	if tick > 0 {
+		end = j.ticks[tick-1]
+	}
+	j.replay(world, end)
+}
+
+// recordInitial records the initial state of the world.
+func (j *Journal) recordInitial(world *ecs.World) {
+	for _, id := range ecs.ComponentIDs(world) {
+		info, _ := ecs.ComponentInfo(world, id)
+		j.components = append(j.components, info)
+	}
+
+	j.dump = world.DumpEntities()
+	for _, idx := range j.dump.Alive {
+		entity := j.dump.Entities[idx]
+		init := initialEntity{Entity: entity}
+		init.Start = uint32(len(j.ids))
+		for _, id := range world.Ids(entity) {
+			j.ids = append(j.ids, id)
+			info, _ := ecs.ComponentInfo(world, id)
+			if info.IsRelation {
+				init.HasRel = true
+				init.Relation = id
+				init.Target = world.Relations().Get(entity, id)
+			}
+		}
+		init.End = uint32(len(j.ids))
+		j.initial = append(j.initial, init)
+	}
+}
+
+// replay the initial state and the given number of records.
+func (j *Journal) replay(world *ecs.World, count int) {
+	for _, info := range j.components {
+		if id := ecs.TypeID(world, info.Type); id != info.ID {
+			panic(fmt.Sprintf("component type %v is registered with a different ID in the replay world", info.Type))
+		}
+	}
+
+	world.LoadEntities(&j.dump)
+	for i := range j.initial {
+		init := &j.initial[i]
+		world.Add(init.Entity, j.ids[init.Start:init.End]...)
+		if init.HasRel && world.Alive(init.Target) {
+			world.Relations().Set(init.Entity, init.Relation, init.Target)
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		j.apply(world, &j.records[i])
+	}
+}
+
+// apply a single record to the world.
+func (j *Journal) apply(world *ecs.World, rec *record) {
+	added := j.ids[rec.AddedStart:rec.AddedEnd]
+	removed := j.ids[rec.AddedEnd:rec.RemovedEnd]
+
+	if rec.EventTypes.Contains(event.EntityRemoved) {
+		world.RemoveEntity(rec.Entity)
+		return
+	}
+	if rec.EventTypes.Contains(event.EntityCreated) {
+		entity := world.NewEntity(added...)
+		if entity != rec.Entity {
+			panic(fmt.Sprintf("replay diverged: expected new entity %v, got %v", rec.Entity, entity))
+		}
+	} else if len(added) > 0 || len(removed) > 0 {
+		world.Exchange(rec.Entity, added, removed)
+	}
+	if rec.HasRelation {
+		world.Relations().Set(rec.Entity, rec.Relation, rec.Target)
+	}
+}
@@ -0,0 +1,190 @@
+package journal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/journal"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X, Y float64
+}
+
+type Velocity struct {
+	X, Y float64
+}
+
+type ChildOf struct {
+	ecs.Relation
+}
+
+func TestJournal(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	relID := ecs.ComponentID[ChildOf](&w)
+
+	parent := w.NewEntity(posID)
+	child := w.NewEntity(posID, relID)
+	w.Relations().Set(child, relID, parent)
+	dead := w.NewEntity()
+	w.RemoveEntity(dead)
+
+	j := journal.New(&w)
+	w.SetListener(&j)
+
+	e1 := w.NewEntity(posID)
+	w.Add(e1, velID)
+	w.Remove(e1, posID)
+	w.Relations().Set(child, relID, e1)
+	j.Tick()
+
+	w.Batch().New(10, posID, velID)
+	builder := ecs.NewBuilder(&w, relID).WithRelation(relID)
+	builder.NewBatch(5, parent)
+	w.RemoveEntity(e1)
+	j.Tick()
+
+	w.Batch().Exchange(ecs.All(posID, velID), nil, []ecs.ID{velID})
+	w.Batch().SetRelation(ecs.All(relID), relID, child)
+	w.Exchange(parent, []ecs.ID{relID}, []ecs.ID{posID})
+	w.Relations().Set(parent, relID, child)
+	exclusive := ecs.All(posID).Exclusive()
+	w.Batch().RemoveEntities(&exclusive)
+	w.NewEntity(velID)
+
+	assert.Equal(t, 2, j.Ticks())
+	assert.Greater(t, j.Len(), 20)
+
+	w2 := ecs.NewWorld()
+	j.Replay(&w2)
+	assertEqualWorlds(t, &w, &w2)
+
+	w3 := ecs.NewWorld()
+	j.ReplayTo(&w3, 0)
+	assert.True(t, w3.Alive(child))
+	assert.Equal(t, parent, w3.Relations().Get(child, relID))
+	assert.Equal(t, e1, w3.NewEntity())
+
+	w4 := ecs.NewWorld()
+	j.ReplayTo(&w4, 1)
+	assert.True(t, w4.Alive(e1))
+	assert.Equal(t, []ecs.ID{velID}, w4.Ids(e1))
+	assert.Equal(t, e1, w4.Relations().Get(child, relID))
+
+	w5 := ecs.NewWorld()
+	j.ReplayTo(&w5, 2)
+	assert.False(t, w5.Alive(e1))
+	query := w5.Query(ecs.All(posID, velID))
+	assert.Equal(t, 10, query.Count())
+	query.Close()
+
+	assert.Panics(t, func() { j.ReplayTo(&w5, 3) })
+	assert.Panics(t, func() { j.ReplayTo(&w5, -1) })
+
+	w6 := ecs.NewWorld()
+	ecs.ComponentID[Velocity](&w6)
+	assert.Panics(t, func() { j.Replay(&w6) })
+}
+
+func TestJournalDeferred(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	relID := ecs.ComponentID[ChildOf](&w)
+
+	parent1 := w.NewEntity(posID)
+	parent2 := w.NewEntity(posID)
+
+	j := journal.New(&w)
+	w.SetListener(&j)
+
+	tx := w.Begin()
+	e1 := ecs.NewBuilder(&w, posID, relID).WithRelation(relID).New(parent1)
+	w.RemoveEntity(e1)
+	e2 := ecs.NewBuilder(&w, posID, relID).WithRelation(relID).New(parent1)
+	w.Relations().Set(e2, relID, parent2)
+	tx.Commit()
+	j.Tick()
+
+	w2 := ecs.NewWorld()
+	j.Replay(&w2)
+	assertEqualWorlds(t, &w, &w2)
+
+	buffered := listener.NewBuffered(&j)
+	w.SetListener(&buffered)
+
+	e3 := ecs.NewBuilder(&w, posID, relID).WithRelation(relID).New(parent2)
+	w.RemoveEntity(e3)
+	w.Relations().Set(e2, relID, parent1)
+	w.RemoveEntity(e2)
+	buffered.Flush(&w)
+	j.Tick()
+
+	w3 := ecs.NewWorld()
+	j.Replay(&w3)
+	assertEqualWorlds(t, &w, &w3)
+
+	w4 := ecs.NewWorld()
+	j.ReplayTo(&w4, 1)
+	assert.Equal(t, parent2, w4.Relations().Get(e2, relID))
+}
+
+func TestJournalNotFresh(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	j := journal.New(&w)
+	w.SetListener(&j)
+	w.NewEntity(posID)
+
+	w2 := ecs.NewWorld()
+	ecs.ComponentID[Position](&w2)
+	w2.NewEntity(posID)
+
+	assert.Panics(t, func() { j.Replay(&w2) })
+}
+
+func assertEqualWorlds(t *testing.T, w1, w2 *ecs.World) {
+	d1, d2 := w1.DumpEntities(), w2.DumpEntities()
+	assert.Equal(t, d1.Entities, d2.Entities)
+	assert.Equal(t, d1.Next, d2.Next)
+	assert.Equal(t, d1.Available, d2.Available)
+	assert.ElementsMatch(t, d1.Alive, d2.Alive)
+
+	for _, idx := range d1.Alive {
+		e := d1.Entities[idx]
+		assert.Equal(t, w1.Ids(e), w2.Ids(e))
+		for _, id := range w1.Ids(e) {
+			if info, _ := ecs.ComponentInfo(w1, id); info.IsRelation {
+				assert.Equal(t, w1.Relations().Get(e, id), w2.Relations().Get(e, id))
+			}
+		}
+	}
+}
+
+func ExampleJournal() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+
+	// Record the initial state, and start recording.
+	j := journal.New(&world)
+	world.SetListener(&j)
+
+	for i := 0; i < 3; i++ {
+		e := world.NewEntity(posID)
+		world.RemoveEntity(world.NewEntity())
+		world.RemoveEntity(e)
+		// Mark the end of a model step.
+		j.Tick()
+	}
+
+	// Reproduce the state after the second step.
+	replay := ecs.NewWorld()
+	j.ReplayTo(&replay, 2)
+	fmt.Println(replay.NewEntity())
+	// Output: {1 2}
+}
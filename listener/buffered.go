@@ -0,0 +1,184 @@
+package listener
+
+import (
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// BufferMode configures the delivery of a [Buffered] listener.
+// Modes can be combined using bitwise OR.
+type BufferMode uint8
+
+const (
+	// BufferReverse delivers buffered events in reverse order, i.e. the most recent event first.
+	// By default, events are delivered in the order they were emitted.
+	BufferReverse BufferMode = 1 << iota
+	// BufferCoalesce drops all events of entities that were created and removed again between two flushes.
+	// The wrapped listener never sees these entities.
+	BufferCoalesce
+)
+
+// Buffered event listener.
+//
+// Queues events and delivers them to a wrapped listener on [Buffered.Flush].
+// In contrast to immediate delivery, the [ecs.World] is not locked during deferred delivery,
+// so the wrapped listener can create and remove entities or modify components.
+//
+// As [ecs.EntityEvent.AddedIDs] and [ecs.EntityEvent.RemovedIDs] are shared slices,
+// as are the relation IDs, they are copied when an event is queued.
+//
+// Note that queued events reflect the state at the time of the event.
+// At flush time, the entities of events may already be dead, or have other components than indicated.
+// Use [ecs.World.Alive] to check before accessing an entity.
+//
+// To make it possible for systems to flush the listener, Buffered can be added to the [ecs.World] as a resource.
+type Buffered struct {
+	listener ecs.Listener            // Wrapped listener to deliver events to.
+	events   []ecs.EntityEvent       // Queued events.
+	flushing []ecs.EntityEvent       // Events currently delivered, to reuse memory.
+	created  map[ecs.Entity]struct{} // Entities created since the last flush, for coalescing.
+	dropped  map[ecs.Entity]struct{} // Entities created and removed since the last flush, for coalescing.
+	ids      []ecs.ID                // Backing memory for copied component IDs.
+	mode     BufferMode              // Delivery options.
+	subs     event.Subscription      // Subscribed event types.
+}
+
+// NewBuffered returns a new [Buffered] listener that wraps the given listener.
+//
+// Without any mode given, events are delivered in the order they were emitted, without coalescing.
+func NewBuffered(listener ecs.Listener, mode ...BufferMode) Buffered {
+	var m BufferMode
+	for _, md := range mode {
+		m |= md
+	}
+	subs := listener.Subscriptions()
+	var created, dropped map[ecs.Entity]struct{}
+	if m&BufferCoalesce != 0 {
+		subs |= event.EntityCreated | event.EntityRemoved
+		created = map[ecs.Entity]struct{}{}
+		dropped = map[ecs.Entity]struct{}{}
+	}
+	return Buffered{
+		listener: listener,
+		mode:     m,
+		subs:     subs,
+		created:  created,
+		dropped:  dropped,
+	}
+}
+
+// Notify the listener. Queues the event for delivery on the next flush.
+func (l *Buffered) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	if l.mode&BufferCoalesce != 0 {
+		if evt.Contains(event.EntityCreated) {
+			l.created[evt.Entity] = struct{}{}
+		}
+		if evt.Contains(event.EntityRemoved) {
+			if _, ok := l.created[evt.Entity]; ok {
+				delete(l.created, evt.Entity)
+				l.dropped[evt.Entity] = struct{}{}
+				return
+			}
+		}
+	}
+
+	evt.AddedIDs = l.copyIDs(evt.AddedIDs)
+	evt.RemovedIDs = l.copyIDs(evt.RemovedIDs)
+	if evt.OldRelation != nil {
+		rel := *evt.OldRelation
+		evt.OldRelation = &rel
+	}
+	if evt.NewRelation != nil {
+		rel := *evt.NewRelation
+		evt.NewRelation = &rel
+	}
+	l.events = append(l.events, evt)
+}
+
+// Flush delivers all queued events to the wrapped listener, and clears the queue.
+//
+// Events emitted by the wrapped listener during the flush are queued for the next flush.
+// Panics if the world is locked.
+func (l *Buffered) Flush(world *ecs.World) {
+	if world.IsLocked() {
+		panic("can't flush buffered events while the world is locked")
+	}
+	events := l.events
+	dropped := l.dropped
+	l.events, l.flushing = l.flushing[:0], nil
+	l.ids = nil
+	if l.mode&BufferCoalesce != 0 {
+		l.created = map[ecs.Entity]struct{}{}
+		l.dropped = map[ecs.Entity]struct{}{}
+	}
+
+	subs := l.listener.Subscriptions()
+	comps := l.listener.Components()
+	deliver := func(evt *ecs.EntityEvent) {
+		if len(dropped) > 0 {
+			if _, ok := dropped[evt.Entity]; ok {
+				return
+			}
+		}
+		trigger := subs & evt.EventTypes
+		if trigger != 0 && subscribes(trigger, &evt.Added, &evt.Removed, comps, evt.OldRelation, evt.NewRelation) {
+			l.listener.Notify(world, *evt)
+		}
+	}
+
+	if l.mode&BufferReverse != 0 {
+		for i := len(events) - 1; i >= 0; i-- {
+			deliver(&events[i])
+		}
+	} else {
+		for i := range events {
+			deliver(&events[i])
+		}
+	}
+
+	clear(events)
+	l.flushing = events[:0]
+}
+
+// Clear drops all queued events without delivering them.
+func (l *Buffered) Clear() {
+	clear(l.events)
+	l.events = l.events[:0]
+	l.ids = nil
+	if l.mode&BufferCoalesce != 0 {
+		clear(l.created)
+		clear(l.dropped)
+	}
+}
+
+// Len returns the number of queued events.
+//
+// With [BufferCoalesce], events of entities that are dropped by coalescing are still counted.
+func (l *Buffered) Len() int {
+	return len(l.events)
+}
+
+// Subscriptions of the listener.
+func (l *Buffered) Subscriptions() event.Subscription {
+	return l.subs
+}
+
+// Components the listener subscribes to.
+//
+// With [BufferCoalesce], subscribes to all components, to catch all entity creations and removals.
+func (l *Buffered) Components() *ecs.Mask {
+	if l.mode&BufferCoalesce != 0 {
+		return nil
+	}
+	return l.listener.Components()
+}
+
+// copyIDs copies component IDs into the listener's backing memory.
+func (l *Buffered) copyIDs(ids []ecs.ID) []ecs.ID {
+	if len(ids) == 0 {
+		return nil
+	}
+	start := len(l.ids)
+	l.ids = append(l.ids, ids...)
+	return l.ids[start:len(l.ids):len(l.ids)]
+}
@@ -0,0 +1,173 @@
+package listener_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffered(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	evt := []ecs.EntityEvent{}
+	inner := listener.NewCallback(
+		func(w *ecs.World, e ecs.EntityEvent) {
+			assert.False(t, w.IsLocked())
+			evt = append(evt, e)
+		},
+		event.EntityCreated|event.EntityRemoved|event.ComponentAdded,
+		posID,
+	)
+	ls := listener.NewBuffered(&inner)
+	w.SetListener(&ls)
+
+	assert.Equal(t, inner.Subscriptions(), ls.Subscriptions())
+	assert.Equal(t, ecs.All(posID), *ls.Components())
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(velID)
+	w.Add(e2, posID)
+	w.RemoveEntity(e1)
+
+	assert.Equal(t, 0, len(evt))
+	assert.Equal(t, 3, ls.Len())
+
+	ls.Flush(&w)
+	assert.Equal(t, 0, ls.Len())
+	assert.Equal(t, 3, len(evt))
+	assert.Equal(t, e1, evt[0].Entity)
+	assert.Equal(t, []ecs.ID{posID}, evt[0].AddedIDs)
+	assert.Equal(t, e2, evt[1].Entity)
+	assert.Equal(t, []ecs.ID{posID}, evt[1].AddedIDs)
+	assert.Equal(t, e1, evt[2].Entity)
+	assert.Equal(t, []ecs.ID{posID}, evt[2].RemovedIDs)
+
+	evt = evt[:0]
+	ls.Flush(&w)
+	assert.Equal(t, 0, len(evt))
+
+	w.NewEntity(posID)
+	ls.Clear()
+	ls.Flush(&w)
+	assert.Equal(t, 0, len(evt))
+
+	query := w.Query(ecs.All())
+	assert.Panics(t, func() { ls.Flush(&w) })
+	query.Close()
+}
+
+func TestBufferedModify(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	var ls listener.Buffered
+	count := 0
+	inner := listener.NewCallback(
+		func(w *ecs.World, e ecs.EntityEvent) {
+			count++
+			if e.Contains(event.EntityRemoved) {
+				return
+			}
+			// Modifying the world is allowed during deferred delivery.
+			w.Add(e.Entity, velID)
+			w.RemoveEntity(e.Entity)
+		},
+		event.EntityCreated|event.EntityRemoved,
+	)
+	ls = listener.NewBuffered(&inner)
+	w.SetListener(&ls)
+
+	w.NewEntity(posID)
+	w.NewEntity(posID)
+
+	ls.Flush(&w)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, ls.Len())
+
+	ls.Flush(&w)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 0, ls.Len())
+}
+
+func TestBufferedModes(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	evt := []ecs.EntityEvent{}
+	inner := listener.NewCallback(
+		func(w *ecs.World, e ecs.EntityEvent) {
+			evt = append(evt, e)
+		},
+		event.ComponentAdded,
+		posID, velID,
+	)
+	ls := listener.NewBuffered(&inner, listener.BufferReverse, listener.BufferCoalesce)
+	w.SetListener(&ls)
+
+	assert.Equal(t, event.ComponentAdded|event.EntityCreated|event.EntityRemoved, ls.Subscriptions())
+	assert.Nil(t, ls.Components())
+
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	w.Add(e1, posID)
+	w.Add(e2, posID)
+	w.Add(e2, velID)
+	w.RemoveEntity(e1)
+	assert.Equal(t, 5, ls.Len())
+
+	ls.Flush(&w)
+	assert.Equal(t, 2, len(evt))
+	assert.Equal(t, e2, evt[0].Entity)
+	assert.Equal(t, []ecs.ID{velID}, evt[0].AddedIDs)
+	assert.Equal(t, e2, evt[1].Entity)
+	assert.Equal(t, []ecs.ID{posID}, evt[1].AddedIDs)
+
+	// Entities created before the last flush are not coalesced.
+	evt = evt[:0]
+	w.Remove(e2, posID)
+	w.Add(e2, posID)
+	w.RemoveEntity(e2)
+	ls.Flush(&w)
+	assert.Equal(t, 1, len(evt))
+
+	evt = evt[:0]
+	e3 := w.NewEntity(posID)
+	ls.Clear()
+	w.RemoveEntity(e3)
+	ls.Flush(&w)
+	assert.Equal(t, 0, len(evt))
+}
+
+func ExampleBuffered() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	inner := listener.NewCallback(
+		func(w *ecs.World, e ecs.EntityEvent) {
+			// The world is unlocked, so handlers can modify it.
+			w.Add(e.Entity, velID)
+			fmt.Println("Added velocity to", e.Entity)
+		},
+		event.EntityCreated,
+		posID,
+	)
+	ls := listener.NewBuffered(&inner, listener.BufferCoalesce)
+	world.SetListener(&ls)
+
+	world.NewEntity(posID)
+	e := world.NewEntity(posID)
+	world.RemoveEntity(e)
+
+	// Deliver events, e.g. at the end of a time step.
+	ls.Flush(&world)
+	// Output: Added velocity to {1 0}
+}
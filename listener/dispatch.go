@@ -11,6 +11,8 @@ import (
 // Sub-listeners should not alter their subscriptions or components after being added.
 //
 // To make it possible for systems to add listeners, Dispatch can be added to the [ecs.World] as a resource.
+// For listeners that need to be removed again, or notified in a certain order,
+// see [ecs.World.AddListener] and [ecs.World.RemoveListener].
 type Dispatch struct {
 	listeners     []ecs.Listener     // Sub-listeners to dispatch events to.
 	events        event.Subscription // Subscribed event types.
@@ -63,6 +65,26 @@ func (l *Dispatch) Notify(world *ecs.World, evt ecs.EntityEvent) {
 	}
 }
 
+// NotifyBatch notifies the listener about a batch event.
+//
+// Forwards the event to sub-listeners implementing [ecs.BatchListener],
+// and falls back to per-entity events for other sub-listeners.
+func (l *Dispatch) NotifyBatch(world *ecs.World, evt ecs.BatchEvent) {
+	for _, ls := range l.listeners {
+		trigger := ls.Subscriptions() & evt.EventTypes
+		if trigger == 0 || !subscribes(trigger, &evt.Added, &evt.Removed, ls.Components(), evt.OldRelation, evt.NewRelation) {
+			continue
+		}
+		if bl, ok := ls.(ecs.BatchListener); ok {
+			bl.NotifyBatch(world, evt)
+			continue
+		}
+		for i := 0; i < evt.Len(); i++ {
+			ls.Notify(world, evt.Event(i))
+		}
+	}
+}
+
 // Subscriptions of the listener.
 func (l *Dispatch) Subscriptions() event.Subscription {
 	return l.events
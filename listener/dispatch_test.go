@@ -212,3 +212,36 @@ func ExampleDispatch() {
 	// Component event on Position
 	// Entity event
 }
+
+type batchHandler struct {
+	listener.Callback
+	batches int
+}
+
+func (h *batchHandler) NotifyBatch(w *ecs.World, e ecs.BatchEvent) {
+	h.batches++
+}
+
+func TestDispatchBatch(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	h1 := EventHandler{}
+	l1 := listener.NewCallback(h1.Notify, event.EntityCreated, posID)
+	l2 := batchHandler{Callback: listener.NewCallback(func(w *ecs.World, e ecs.EntityEvent) {}, event.All)}
+	l3 := batchHandler{Callback: listener.NewCallback(func(w *ecs.World, e ecs.EntityEvent) {}, event.All, velID)}
+
+	ls := listener.NewDispatch(&l1, &l2, &l3)
+	world.SetListener(&ls)
+
+	world.Batch().New(10, posID)
+	assert.Equal(t, 10, len(h1.events))
+	assert.Equal(t, 1, l2.batches)
+	assert.Equal(t, 0, l3.batches)
+
+	world.Batch().New(10, velID)
+	assert.Equal(t, 10, len(h1.events))
+	assert.Equal(t, 2, l2.batches)
+	assert.Equal(t, 1, l3.batches)
+}
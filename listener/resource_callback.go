@@ -0,0 +1,47 @@
+package listener
+
+import (
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// ResourceCallback listener for ecs.ResourceEvent.
+//
+// Calls a function on resource events that are contained in the subscription mask.
+type ResourceCallback struct {
+	callback     func(w *ecs.World, e ecs.ResourceEvent)
+	events       event.ResourceSubscription
+	resources    ecs.Mask
+	hasResources bool
+}
+
+// NewResourceCallback creates a new ResourceCallback listener for the given events.
+//
+// Subscribes to the specified events on the specified resources.
+// If no resource IDs are given, it subscribes to all resources.
+func NewResourceCallback(callback func(*ecs.World, ecs.ResourceEvent), events event.ResourceSubscription, resources ...ecs.ResID) ResourceCallback {
+	return ResourceCallback{
+		callback:     callback,
+		events:       events,
+		resources:    ecs.ResourceMask(resources...),
+		hasResources: len(resources) > 0,
+	}
+}
+
+// Notify the listener.
+func (l *ResourceCallback) Notify(w *ecs.World, e ecs.ResourceEvent) {
+	l.callback(w, e)
+}
+
+// Subscriptions of the listener.
+func (l *ResourceCallback) Subscriptions() event.ResourceSubscription {
+	return l.events
+}
+
+// Resources the listener subscribes to.
+func (l *ResourceCallback) Resources() *ecs.Mask {
+	if l.hasResources {
+		return &l.resources
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package listener_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceCallback(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ResourceID[Position](&w)
+	velID := ecs.ResourceID[Velocity](&w)
+
+	evt := []ecs.ResourceEvent{}
+	ls := listener.NewResourceCallback(
+		func(w *ecs.World, e ecs.ResourceEvent) {
+			evt = append(evt, e)
+		},
+		event.ResourceAdded|event.ResourceRemoved,
+		posID,
+	)
+	w.SetResourceListener(&ls)
+
+	assert.Equal(t, event.ResourceAdded|event.ResourceRemoved, ls.Subscriptions())
+	assert.Equal(t, ecs.ResourceMask(posID), *ls.Resources())
+
+	w.Resources().Add(posID, &Position{})
+	assert.Equal(t, 1, len(evt))
+	assert.Equal(t, posID, evt[0].Resource)
+	assert.True(t, evt[0].Contains(event.ResourceAdded))
+
+	w.Resources().Add(velID, &Velocity{})
+	assert.Equal(t, 1, len(evt))
+
+	w.Resources().Set(posID, &Position{})
+	assert.Equal(t, 1, len(evt))
+
+	w.Resources().Remove(posID)
+	assert.Equal(t, 2, len(evt))
+	assert.True(t, evt[1].Contains(event.ResourceRemoved))
+
+	ls = listener.NewResourceCallback(
+		func(w *ecs.World, e ecs.ResourceEvent) {
+			evt = append(evt, e)
+		},
+		event.Resources,
+	)
+	assert.Nil(t, ls.Resources())
+
+	w.Resources().Set(velID, &Velocity{})
+	assert.Equal(t, 3, len(evt))
+	assert.True(t, evt[2].Contains(event.ResourceChanged))
+}
+
+func ExampleResourceCallback() {
+	world := ecs.NewWorld()
+
+	posID := ecs.ResourceID[Position](&world)
+
+	ls := listener.NewResourceCallback(
+		func(w *ecs.World, e ecs.ResourceEvent) {
+			// Print the EventType bits of the event.
+			fmt.Printf("   EventType: %08b\n", e.EventTypes)
+		},
+		// Subscribe to all resource events.
+		event.Resources,
+	)
+	world.SetResourceListener(&ls)
+
+	fmt.Println("Add resource")
+	world.Resources().Add(posID, &Position{})
+
+	fmt.Println("Replace resource")
+	world.Resources().Set(posID, &Position{X: 1})
+
+	fmt.Println("Remove resource")
+	world.Resources().Remove(posID)
+	// Output: Add resource
+	//    EventType: 00000001
+	// Replace resource
+	//    EventType: 00000100
+	// Remove resource
+	//    EventType: 00000010
+}
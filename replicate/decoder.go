@@ -0,0 +1,333 @@
+package replicate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// decodedType is a source component type known to the [Decoder].
+type decodedType struct {
+	id            ecs.ID    // Component ID in the replica world.
+	size          uintptr   // Size of the type in the source world.
+	entityOffsets []uintptr // Memory offsets of entity fields.
+	known         bool      // Whether the type is registered in the replica world.
+	relation      bool      // Whether the type is a relation.
+	replicated    bool      // Whether the type's values are replicated.
+}
+
+// record is a decoded entity record.
+type record struct {
+	entity     sourceEntity
+	target     sourceEntity
+	components []uint8
+	values     []value
+	flags      byte
+}
+
+// value is a decoded component value.
+type value struct {
+	data  []byte
+	index uint8
+}
+
+// Decoder applies deltas written by an [Encoder] to a replica world.
+//
+// Entities of the source world are mapped to entities of the replica world.
+// Entity IDs and generations differ between source and replica in general.
+// Relation targets and [ecs.Entity] fields of replicated components are re-mapped accordingly.
+// Fields referring to entities that are unknown to the replica are set to the zero entity.
+//
+// Component types are matched by their package path and name.
+// Component types that are not registered in the replica world are ignored.
+//
+// The replica world may contain other entities than the replicated ones.
+// These are not affected by the decoder.
+type Decoder struct {
+	world    *ecs.World
+	types    []decodedType
+	byName   map[string]ecs.ID
+	entities map[sourceEntity]ecs.Entity
+	frame    []byte
+	removed  []sourceEntity
+	records  []record
+	ids      []ecs.ID
+	add      []ecs.ID
+	rem      []ecs.ID
+	synced   bool
+}
+
+// NewDecoder creates a new [Decoder] for the given replica world.
+//
+// Component types to replicate must be registered in the replica world before decoding.
+func NewDecoder(world *ecs.World) *Decoder {
+	return &Decoder{
+		world:    world,
+		byName:   map[string]ecs.ID{},
+		entities: map[sourceEntity]ecs.Entity{},
+	}
+}
+
+// Entity returns the replica entity for an entity of the source world.
+// The second return value is false if the source entity is unknown to the replica.
+func (d *Decoder) Entity(source ecs.Entity) (ecs.Entity, bool) {
+	e, ok := d.entities[sourceEntity{id: source.ID(), gen: source.Generation()}]
+	return e, ok
+}
+
+// Len returns the number of replicated entities.
+func (d *Decoder) Len() int {
+	return len(d.entities)
+}
+
+// Decode reads a single delta from the given reader, and applies it to the replica world.
+//
+// Returns [io.EOF] if the reader has no more data at the start of a delta.
+// On any other error, the replica may be left in an inconsistent state,
+// and should be re-synchronized by a full snapshot, see [Encoder.Reset].
+//
+// The world must not be locked, i.e. no queries must be open.
+func (d *Decoder) Decode(r io.Reader) error {
+	frame, err := readFrame(r, d.frame)
+	d.frame = frame
+	if err != nil {
+		return err
+	}
+
+	rd := reader{buf: frame}
+	if version := rd.byte(); rd.err == nil && version != formatVersion {
+		return fmt.Errorf("unsupported delta format version %d", version)
+	}
+	if flags := rd.byte(); flags&deltaFull != 0 {
+		d.clear()
+		d.synced = true
+	} else if rd.err == nil && !d.synced {
+		return errors.New("delta without a preceding full snapshot")
+	}
+	if err := d.readTypes(&rd); err != nil {
+		return err
+	}
+	d.readRecords(&rd)
+	if rd.err != nil {
+		return rd.err
+	}
+	if rd.pos != len(rd.buf) {
+		return errMalformed
+	}
+	return d.apply()
+}
+
+// clear removes all replicated entities, and forgets all component types, for a full snapshot.
+func (d *Decoder) clear() {
+	for _, e := range d.entities {
+		if d.world.Alive(e) {
+			d.world.RemoveEntity(e)
+		}
+	}
+	clear(d.entities)
+	d.types = d.types[:0]
+}
+
+// readTypes reads component types and matches them with the replica world's types.
+func (d *Decoder) readTypes(rd *reader) error {
+	count := rd.count()
+	for i := 0; i < count; i++ {
+		index := int(rd.byte())
+		flags := rd.byte()
+		size := uintptr(rd.uvarint())
+		name := string(rd.bytes(rd.count()))
+		if rd.err != nil {
+			return rd.err
+		}
+		if index != len(d.types) {
+			return errMalformed
+		}
+
+		tp := decodedType{
+			size:       size,
+			relation:   flags&typeRelation != 0,
+			replicated: flags&typeReplicated != 0,
+		}
+		if id, ok := d.lookup(name); ok {
+			info, _ := ecs.ComponentInfo(d.world, id)
+			if info.IsRelation != tp.relation {
+				return fmt.Errorf("component type %s is a relation in only one of source and replica", name)
+			}
+			if tp.replicated {
+				if info.Type.Size() != size {
+					return fmt.Errorf("component type %s has size %d in the source, but %d in the replica", name, size, info.Type.Size())
+				}
+				if hasPointers(info.Type) {
+					return fmt.Errorf("component type %s contains pointers in the replica", name)
+				}
+				tp.entityOffsets = entityOffsets(info.Type, 0, nil)
+			}
+			tp.id = id
+			tp.known = true
+		}
+		d.types = append(d.types, tp)
+	}
+	return nil
+}
+
+// lookup finds a component type of the replica world by name.
+func (d *Decoder) lookup(name string) (ecs.ID, bool) {
+	if id, ok := d.byName[name]; ok {
+		return id, true
+	}
+	for _, id := range ecs.ComponentIDs(d.world) {
+		info, _ := ecs.ComponentInfo(d.world, id)
+		d.byName[typeName(info.Type)] = id
+	}
+	id, ok := d.byName[name]
+	return id, ok
+}
+
+// readRecords reads removed entities and entity records.
+func (d *Decoder) readRecords(rd *reader) {
+	d.removed = d.removed[:0]
+	count := rd.count()
+	for i := 0; i < count; i++ {
+		d.removed = append(d.removed, rd.entity())
+	}
+
+	d.records = d.records[:0]
+	count = rd.count()
+	for i := 0; i < count && rd.err == nil; i++ {
+		d.records = append(d.records, record{})
+		rec := &d.records[len(d.records)-1]
+		rec.entity = rd.entity()
+		rec.flags = rd.byte()
+		if rec.flags&recordComponents != 0 {
+			rec.components = rd.bytes(rd.count())
+			for _, idx := range rec.components {
+				if int(idx) >= len(d.types) {
+					rd.err = errMalformed
+				}
+			}
+		}
+		if rec.flags&recordTarget != 0 {
+			rec.target = rd.entity()
+		}
+		if rec.flags&recordValues != 0 {
+			n := rd.count()
+			for j := 0; j < n && rd.err == nil; j++ {
+				idx := rd.byte()
+				if int(idx) >= len(d.types) || !d.types[idx].replicated {
+					rd.err = errMalformed
+					break
+				}
+				rec.values = append(rec.values, value{index: idx, data: rd.bytes(int(d.types[idx].size))})
+			}
+		}
+	}
+}
+
+// apply the decoded delta to the replica world.
+//
+// Entities are removed and created first, so that relation targets
+// and entity fields can refer to entities created by the same delta.
+func (d *Decoder) apply() error {
+	for _, src := range d.removed {
+		if e, ok := d.entities[src]; ok {
+			if d.world.Alive(e) {
+				d.world.RemoveEntity(e)
+			}
+			delete(d.entities, src)
+		}
+	}
+
+	for i := range d.records {
+		rec := &d.records[i]
+		e, ok := d.entities[rec.entity]
+		if rec.flags&recordCreated != 0 {
+			if ok {
+				return fmt.Errorf("created entity %v is already known", rec.entity)
+			}
+			d.entities[rec.entity] = d.world.NewEntity()
+		} else if !ok {
+			return fmt.Errorf("entity %v is unknown", rec.entity)
+		} else if !d.world.Alive(e) {
+			return fmt.Errorf("replica entity for %v was removed", rec.entity)
+		}
+	}
+
+	for i := range d.records {
+		rec := &d.records[i]
+		e := d.entities[rec.entity]
+		if rec.flags&recordComponents != 0 {
+			d.exchange(e, rec.components)
+		}
+		if rec.flags&recordTarget != 0 {
+			d.setTarget(e, rec.target)
+		}
+		for _, val := range rec.values {
+			tp := &d.types[val.index]
+			if !tp.known {
+				continue
+			}
+			ptr := d.world.Get(e, tp.id)
+			copy(unsafe.Slice((*byte)(ptr), tp.size), val.data)
+			for _, off := range tp.entityOffsets {
+				field := (*ecs.Entity)(unsafe.Add(ptr, off))
+				*field = d.mapEntity(sourceEntity{id: field.ID(), gen: field.Generation()})
+			}
+		}
+	}
+	return nil
+}
+
+// exchange components of a replica entity to match the given source component set.
+func (d *Decoder) exchange(e ecs.Entity, components []uint8) {
+	d.ids = d.ids[:0]
+	for _, idx := range components {
+		if tp := &d.types[idx]; tp.known {
+			d.ids = append(d.ids, tp.id)
+		}
+	}
+	target := ecs.All(d.ids...)
+	current := ecs.All()
+
+	d.rem = d.rem[:0]
+	for _, id := range d.world.Ids(e) {
+		current.Set(id, true)
+		if !target.Get(id) {
+			d.rem = append(d.rem, id)
+		}
+	}
+	d.add = d.add[:0]
+	for _, id := range d.ids {
+		if !current.Get(id) {
+			d.add = append(d.add, id)
+		}
+	}
+	if len(d.add) > 0 || len(d.rem) > 0 {
+		d.world.Exchange(e, d.add, d.rem)
+	}
+}
+
+// setTarget sets the relation target of a replica entity.
+func (d *Decoder) setTarget(e ecs.Entity, target sourceEntity) {
+	for _, id := range d.world.Ids(e) {
+		info, _ := ecs.ComponentInfo(d.world, id)
+		if info.IsRelation {
+			d.world.Relations().Set(e, id, d.mapEntity(target))
+			return
+		}
+	}
+}
+
+// mapEntity maps a source entity to a replica entity.
+// Returns the zero entity for unknown or dead entities.
+func (d *Decoder) mapEntity(src sourceEntity) ecs.Entity {
+	if src.id == 0 {
+		return ecs.Entity{}
+	}
+	if e, ok := d.entities[src]; ok && d.world.Alive(e) {
+		return e
+	}
+	return ecs.Entity{}
+}
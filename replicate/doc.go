@@ -0,0 +1,17 @@
+// Package replicate provides delta snapshots for mirroring the state of a world into a replica,
+// for Arche, an Entity Component System (ECS) for Go.
+//
+// An [Encoder] compares a source [github.com/mlange-42/arche/ecs.World] against the baseline of its previous delta,
+// and writes a compact binary delta of created and removed entities, changed component sets,
+// changed relation targets, and changed values of opted-in component types.
+// A [Decoder] applies deltas to a replica world, and maps source entities to replica entities.
+//
+// Deltas are written to an [io.Writer] and read from an [io.Reader],
+// so they can be sent over any stream, like a network connection, a pipe or a [bytes.Buffer].
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package replicate
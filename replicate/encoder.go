@@ -0,0 +1,274 @@
+package replicate
+
+import (
+	"bytes"
+	"cmp"
+	"io"
+	"slices"
+	"unsafe"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// encodedType is a component type known to the [Encoder].
+type encodedType struct {
+	info       ecs.CompInfo
+	size       uintptr
+	replicated bool
+}
+
+// entityState is the baseline state of an entity.
+type entityState struct {
+	mask   ecs.Mask   // Component mask.
+	target ecs.Entity // Relation target.
+	values []byte     // Values of replicated components, concatenated in the order of [Encoder.replicated].
+	epoch  uint32     // Epoch of the last delta the entity was seen in.
+}
+
+// Encoder writes deltas of a world's state, relative to the state at the previous delta.
+//
+// The first delta, and the first delta after [Encoder.Reset], is a full snapshot.
+// Deltas must be applied by a [Decoder] in the order they were written.
+//
+// Component values are only transferred for the replicated component types given to [NewEncoder].
+// Their values are transferred in their native memory layout, so encoder and decoder
+// must run on the same architecture. [ecs.Entity] fields in replicated components are re-mapped by the decoder.
+// Resources are not replicated.
+type Encoder struct {
+	world      *ecs.World
+	types      []encodedType
+	indices    map[ecs.ID]uint8
+	replicated []uint8 // Indices of replicated types.
+	relations  []uint8 // Indices of relation types.
+	baseline   map[ecs.Entity]*entityState
+	epoch      uint32
+	sentTypes  int
+	full       bool
+	body       writer
+	records    writer
+	frame      writer
+	removed    []ecs.Entity
+	changed    []uint8
+	values     []byte
+}
+
+// NewEncoder creates a new [Encoder] for the given world.
+//
+// Values of the given component types are replicated.
+// Other components are only replicated as part of entities' component sets.
+//
+// Panics if a replicated component type contains pointers,
+// like pointers, slices, maps, strings or interfaces.
+func NewEncoder(world *ecs.World, replicated ...ecs.ID) *Encoder {
+	e := &Encoder{
+		world:    world,
+		indices:  map[ecs.ID]uint8{},
+		baseline: map[ecs.Entity]*entityState{},
+		full:     true,
+	}
+	e.updateTypes()
+
+	for _, id := range replicated {
+		info, ok := ecs.ComponentInfo(world, id)
+		if !ok {
+			panic("can't replicate an unregistered component type")
+		}
+		checkReplicable(info.Type)
+		idx := e.indices[id]
+		if e.types[idx].replicated {
+			continue
+		}
+		e.types[idx].replicated = true
+		e.replicated = append(e.replicated, idx)
+	}
+	slices.Sort(e.replicated)
+	return e
+}
+
+// Reset the encoder's baseline. The next delta will be a full snapshot.
+//
+// Use this when a new replica is connected.
+func (e *Encoder) Reset() {
+	clear(e.baseline)
+	e.sentTypes = 0
+	e.full = true
+}
+
+// Encode writes a delta of the world's state, relative to the state at the previous delta, to the given writer.
+// The delta is written with a single call to the writer's Write method.
+//
+// The world must not be locked, i.e. no queries must be open.
+func (e *Encoder) Encode(w io.Writer) error {
+	e.updateTypes()
+	e.epoch++
+
+	e.body.buf = e.body.buf[:0]
+	e.body.byte(formatVersion)
+	if e.full {
+		e.body.byte(deltaFull)
+	} else {
+		e.body.byte(0)
+	}
+	e.writeTypes()
+
+	e.records.buf = e.records.buf[:0]
+	count := 0
+	query := e.world.Query(ecs.All())
+	for query.Next() {
+		if e.encodeEntity(&query) {
+			count++
+		}
+	}
+
+	e.removed = e.removed[:0]
+	for entity, state := range e.baseline {
+		if state.epoch != e.epoch {
+			e.removed = append(e.removed, entity)
+		}
+	}
+	slices.SortFunc(e.removed, func(a, b ecs.Entity) int {
+		return cmp.Compare(a.ID(), b.ID())
+	})
+	e.body.uvarint(uint64(len(e.removed)))
+	for _, entity := range e.removed {
+		e.body.entity(entity)
+		delete(e.baseline, entity)
+	}
+
+	e.body.uvarint(uint64(count))
+	e.body.bytes(e.records.buf)
+
+	e.frame.buf = e.frame.buf[:0]
+	e.frame.uvarint(uint64(len(e.body.buf)))
+	e.frame.bytes(e.body.buf)
+	e.full = false
+
+	_, err := w.Write(e.frame.buf)
+	return err
+}
+
+// updateTypes registers component types that were registered in the world since the last call.
+func (e *Encoder) updateTypes() {
+	ids := ecs.ComponentIDs(e.world)
+	for i := len(e.types); i < len(ids); i++ {
+		info, _ := ecs.ComponentInfo(e.world, ids[i])
+		e.types = append(e.types, encodedType{info: info, size: info.Type.Size()})
+		e.indices[info.ID] = uint8(i)
+		if info.IsRelation {
+			e.relations = append(e.relations, uint8(i))
+		}
+	}
+}
+
+// writeTypes writes component types not yet sent to the decoder.
+func (e *Encoder) writeTypes() {
+	e.body.uvarint(uint64(len(e.types) - e.sentTypes))
+	for i := e.sentTypes; i < len(e.types); i++ {
+		tp := &e.types[i]
+		var flags byte
+		if tp.info.IsRelation {
+			flags |= typeRelation
+		}
+		if tp.replicated {
+			flags |= typeReplicated
+		}
+		name := typeName(tp.info.Type)
+		e.body.byte(uint8(i))
+		e.body.byte(flags)
+		e.body.uvarint(uint64(tp.size))
+		e.body.uvarint(uint64(len(name)))
+		e.body.bytes([]byte(name))
+	}
+	e.sentTypes = len(e.types)
+}
+
+// encodeEntity compares the entity at the query's position to the baseline,
+// and writes a record if anything changed. Updates the baseline.
+func (e *Encoder) encodeEntity(query *ecs.Query) bool {
+	entity := query.Entity()
+	mask := query.Mask()
+
+	state, ok := e.baseline[entity]
+	if !ok {
+		state = &entityState{}
+		e.baseline[entity] = state
+	}
+	state.epoch = e.epoch
+
+	var flags byte
+	if !ok {
+		flags |= recordCreated
+	}
+	if !ok || mask != state.mask {
+		flags |= recordComponents
+	}
+
+	target := ecs.Entity{}
+	hasTarget := false
+	for _, idx := range e.relations {
+		if id := e.types[idx].info.ID; mask.Get(id) {
+			target = query.Relation(id)
+			hasTarget = true
+			break
+		}
+	}
+	if target != state.target || (hasTarget && flags&recordComponents != 0) {
+		flags |= recordTarget
+	}
+
+	e.changed = e.changed[:0]
+	values := e.values[:0]
+	oldOffset := uintptr(0)
+	for _, idx := range e.replicated {
+		tp := &e.types[idx]
+		had := state.mask.Get(tp.info.ID) && ok
+		if !mask.Get(tp.info.ID) {
+			if had {
+				oldOffset += tp.size
+			}
+			continue
+		}
+		current := unsafe.Slice((*byte)(query.Get(tp.info.ID)), tp.size)
+		if !had || !bytes.Equal(state.values[oldOffset:oldOffset+tp.size], current) {
+			e.changed = append(e.changed, idx)
+		}
+		if had {
+			oldOffset += tp.size
+		}
+		values = append(values, current...)
+	}
+	state.values = append(state.values[:0], values...)
+	e.values = values
+	if len(e.changed) > 0 {
+		flags |= recordValues
+	}
+
+	state.mask = mask
+	state.target = target
+
+	if flags == 0 {
+		return false
+	}
+
+	w := &e.records
+	w.entity(entity)
+	w.byte(flags)
+	if flags&recordComponents != 0 {
+		ids := query.Ids()
+		w.uvarint(uint64(len(ids)))
+		for _, id := range ids {
+			w.byte(e.indices[id])
+		}
+	}
+	if flags&recordTarget != 0 {
+		w.entity(target)
+	}
+	if flags&recordValues != 0 {
+		w.uvarint(uint64(len(e.changed)))
+		for _, idx := range e.changed {
+			w.byte(idx)
+			w.bytes(unsafe.Slice((*byte)(query.Get(e.types[idx].info.ID)), e.types[idx].size))
+		}
+	}
+	return true
+}
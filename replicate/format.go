@@ -0,0 +1,225 @@
+package replicate
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Binary format of a delta, with all integers as unsigned varints unless noted otherwise:
+//
+//	frame:   length body
+//	body:    version(byte) flags(byte) types removed changed
+//	types:   count { index(byte) typeFlags(byte) size nameLength name }
+//	removed: count { entity }
+//	changed: count { entity recordFlags(byte) [components] [target] [values] }
+//	entity:  id generation
+//	components: count { index(byte) }
+//	target:     entity
+//	values:     count { index(byte) raw bytes of the component }
+//
+// Component types are identified by their index in the source world's registered types.
+// Component values are transferred in their native memory layout.
+
+const formatVersion = 1
+
+// Delta flags.
+const (
+	deltaFull byte = 1 << iota // The delta is a full snapshot.
+)
+
+// Component type flags.
+const (
+	typeRelation   byte = 1 << iota // The type is a relation.
+	typeReplicated                  // Values of the type are replicated.
+)
+
+// Entity record flags.
+const (
+	recordCreated    byte = 1 << iota // The entity was created.
+	recordComponents                  // The entity's component set changed.
+	recordTarget                      // The entity's relation target changed.
+	recordValues                      // Component values changed.
+)
+
+// errMalformed is returned when decoding a malformed delta.
+var errMalformed = errors.New("malformed delta")
+
+var entityType = reflect.TypeOf(ecs.Entity{})
+
+// typeName returns a name that identifies a component type across processes.
+func typeName(tp reflect.Type) string {
+	if tp.Name() == "" || tp.PkgPath() == "" {
+		return tp.String()
+	}
+	return tp.PkgPath() + "." + tp.Name()
+}
+
+// checkReplicable panics if a type contains pointers, and can thus not be transferred as raw memory.
+func checkReplicable(tp reflect.Type) {
+	if hasPointers(tp) {
+		panic(fmt.Sprintf("component type %v contains pointers and can't be replicated", tp))
+	}
+}
+
+// hasPointers checks whether a type contains pointers.
+func hasPointers(tp reflect.Type) bool {
+	switch tp.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return tp.Len() > 0 && hasPointers(tp.Elem())
+	case reflect.Struct:
+		for i := 0; i < tp.NumField(); i++ {
+			if hasPointers(tp.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// entityOffsets appends the memory offsets of all [ecs.Entity] fields in a type.
+func entityOffsets(tp reflect.Type, base uintptr, offsets []uintptr) []uintptr {
+	if tp == entityType {
+		return append(offsets, base)
+	}
+	switch tp.Kind() {
+	case reflect.Array:
+		for i := 0; i < tp.Len(); i++ {
+			offsets = entityOffsets(tp.Elem(), base+uintptr(i)*tp.Elem().Size(), offsets)
+		}
+	case reflect.Struct:
+		for i := 0; i < tp.NumField(); i++ {
+			field := tp.Field(i)
+			offsets = entityOffsets(field.Type, base+field.Offset, offsets)
+		}
+	}
+	return offsets
+}
+
+// sourceEntity is an entity of the source world.
+type sourceEntity struct {
+	id  uint32
+	gen uint32
+}
+
+// writer appends the binary representation of values to a buffer.
+type writer struct {
+	buf []byte
+}
+
+func (w *writer) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *writer) uvarint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *writer) entity(e ecs.Entity) {
+	w.uvarint(uint64(e.ID()))
+	w.uvarint(uint64(e.Generation()))
+}
+
+func (w *writer) bytes(b []byte) {
+	w.buf = append(w.buf, b...)
+}
+
+// reader reads values from a buffer. Errors are sticky, and result in zero values.
+type reader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil || r.pos >= len(r.buf) {
+		r.err = errMalformed
+		return 0
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		r.err = errMalformed
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *reader) count() int {
+	v := r.uvarint()
+	if v > uint64(len(r.buf)-r.pos) {
+		// Every counted element takes at least one byte.
+		r.err = errMalformed
+		return 0
+	}
+	return int(v)
+}
+
+func (r *reader) entity() sourceEntity {
+	return sourceEntity{id: uint32(r.uvarint()), gen: uint32(r.uvarint())}
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.buf)-r.pos {
+		r.err = errMalformed
+		return nil
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// readFrame reads a length-prefixed frame into the given buffer.
+func readFrame(rd io.Reader, buf []byte) ([]byte, error) {
+	length, err := binary.ReadUvarint(byteReader{rd})
+	if err != nil {
+		if err == io.EOF {
+			return buf, err
+		}
+		return buf, fmt.Errorf("reading frame length: %w", err)
+	}
+	if uint64(cap(buf)) < length {
+		buf = make([]byte, length)
+	}
+	buf = buf[:length]
+	if _, err := io.ReadFull(rd, buf); err != nil {
+		return buf, fmt.Errorf("reading frame: %w", err)
+	}
+	return buf, nil
+}
+
+// byteReader reads single bytes from a reader,
+// to avoid buffering beyond the end of a frame.
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+	if br, ok := r.Reader.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	var b [1]byte
+	if _, err := io.ReadFull(r.Reader, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
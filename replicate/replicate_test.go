@@ -0,0 +1,358 @@
+package replicate_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/replicate"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X, Y float64
+}
+
+type Velocity struct {
+	X, Y float64
+}
+
+type Label struct {
+	Name string
+}
+
+type Follow struct {
+	Target [2]ecs.Entity
+	Speed  int
+}
+
+type ChildOf struct {
+	ecs.Relation
+	Index int
+}
+
+// sourceState describes the source world, with entities mapped to the replica and values of the given types.
+func sourceState(w *ecs.World, dec *replicate.Decoder, replicated ...ecs.ID) map[ecs.Entity]string {
+	mapEntity := func(e ecs.Entity) ecs.Entity {
+		r, _ := dec.Entity(e)
+		return r
+	}
+	return worldState(w, mapEntity, replicated...)
+}
+
+// worldState describes all entities of a world, with their component types, values of the given types
+// and relation targets. Dead targets are described as the zero entity.
+func worldState(w *ecs.World, mapEntity func(ecs.Entity) ecs.Entity, replicated ...ecs.ID) map[ecs.Entity]string {
+	replicatedTypes := map[reflect.Type]bool{}
+	for _, id := range replicated {
+		info, _ := ecs.ComponentInfo(w, id)
+		replicatedTypes[info.Type] = true
+	}
+
+	state := map[ecs.Entity]string{}
+	query := w.Query(ecs.All())
+	for query.Next() {
+		parts := []string{}
+		for _, id := range query.Ids() {
+			info, _ := ecs.ComponentInfo(w, id)
+			desc := info.Type.Name()
+			if replicatedTypes[info.Type] {
+				value := reflect.NewAt(info.Type, query.Get(id)).Elem().Interface()
+				if follow, ok := value.(Follow); ok {
+					follow.Target[0] = mapEntity(follow.Target[0])
+					follow.Target[1] = mapEntity(follow.Target[1])
+					value = follow
+				}
+				desc += fmt.Sprintf("%+v", value)
+			}
+			if info.IsRelation {
+				target := query.Relation(id)
+				if !w.Alive(target) {
+					target = ecs.Entity{}
+				}
+				desc += fmt.Sprintf("->%v", mapEntity(target))
+			}
+			parts = append(parts, desc)
+		}
+		sort.Strings(parts)
+		state[mapEntity(query.Entity())] = strings.Join(parts, " ")
+	}
+	return state
+}
+
+func TestReplicate(t *testing.T) {
+	src := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&src)
+	velID := ecs.ComponentID[Velocity](&src)
+	labelID := ecs.ComponentID[Label](&src)
+	followID := ecs.ComponentID[Follow](&src)
+	relID := ecs.ComponentID[ChildOf](&src)
+
+	dst := ecs.NewWorld()
+	dstRelID := ecs.ComponentID[ChildOf](&dst)
+	ecs.ComponentID[Follow](&dst)
+	ecs.ComponentID[Label](&dst)
+	ecs.ComponentID[Velocity](&dst)
+	dstPosID := ecs.ComponentID[Position](&dst)
+	other := dst.NewEntity(dstPosID)
+
+	enc := replicate.NewEncoder(&src, posID, velID, followID, relID)
+	dec := replicate.NewDecoder(&dst)
+	buf := bytes.Buffer{}
+
+	identity := func(e ecs.Entity) ecs.Entity { return e }
+	check := func() {
+		assert.Nil(t, enc.Encode(&buf))
+		assert.Nil(t, dec.Decode(&buf))
+		assert.Equal(t, 0, buf.Len())
+
+		expected := sourceState(&src, dec, posID, velID, followID, relID)
+		actual := worldState(&dst, identity, ecs.ComponentID[Position](&dst), ecs.ComponentID[Velocity](&dst),
+			ecs.ComponentID[Follow](&dst), ecs.ComponentID[ChildOf](&dst))
+		delete(actual, other)
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, len(expected), dec.Len())
+	}
+
+	parent := src.NewEntity(posID, labelID)
+	(*Position)(src.Get(parent, posID)).X = 1
+	(*Label)(src.Get(parent, labelID)).Name = "parent"
+	children := []ecs.Entity{}
+	for i := 0; i < 5; i++ {
+		child := ecs.NewBuilder(&src, posID, velID, relID).WithRelation(relID).New(parent)
+		(*Velocity)(src.Get(child, velID)).X = float64(i)
+		(*ChildOf)(src.Get(child, relID)).Index = i
+		children = append(children, child)
+	}
+	follower := src.NewEntity(followID)
+	follow := (*Follow)(src.Get(follower, followID))
+	follow.Target = [2]ecs.Entity{children[0], parent}
+	check()
+
+	replicaParent, ok := dec.Entity(parent)
+	assert.True(t, ok)
+	assert.NotEqual(t, parent, replicaParent)
+	assert.Equal(t, replicaParent, dst.Relations().Get(must(dec.Entity(children[2])), dstRelID))
+	assert.Equal(t, Position{X: 1}, *(*Position)(dst.Get(replicaParent, dstPosID)))
+	assert.True(t, dst.Alive(other))
+
+	query := src.Query(ecs.All(posID, velID))
+	for query.Next() {
+		pos := (*Position)(query.Get(posID))
+		vel := (*Velocity)(query.Get(velID))
+		pos.X += vel.X
+	}
+	check()
+
+	newParent := src.NewEntity(posID)
+	src.Relations().Set(children[1], relID, newParent)
+	src.Remove(children[2], velID)
+	src.Add(children[3], labelID)
+	src.RemoveEntity(children[0])
+	(*Follow)(src.Get(follower, followID)).Target[1] = newParent
+	check()
+
+	src.RemoveEntity(parent)
+	src.Exchange(children[4], []ecs.ID{labelID}, []ecs.ID{relID})
+	src.Batch().New(5, posID, velID)
+	check()
+
+	_, ok = dec.Entity(parent)
+	assert.False(t, ok)
+	assert.False(t, dst.Alive(replicaParent))
+
+	src.Batch().RemoveEntities(ecs.All(velID))
+	src.NewEntity(posID, velID)
+	check()
+}
+
+func TestReplicateUnchanged(t *testing.T) {
+	src := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&src)
+	src.Batch().New(100, posID)
+
+	dst := ecs.NewWorld()
+	ecs.ComponentID[Position](&dst)
+
+	enc := replicate.NewEncoder(&src, posID)
+	dec := replicate.NewDecoder(&dst)
+	buf := bytes.Buffer{}
+
+	assert.Nil(t, enc.Encode(&buf))
+	full := buf.Len()
+	assert.Nil(t, dec.Decode(&buf))
+
+	assert.Nil(t, enc.Encode(&buf))
+	assert.Less(t, buf.Len(), 10)
+	assert.Nil(t, dec.Decode(&buf))
+
+	query := src.Query(ecs.All(posID))
+	query.Next()
+	(*Position)(query.Get(posID)).X = 1
+	query.Close()
+
+	assert.Nil(t, enc.Encode(&buf))
+	assert.Less(t, buf.Len(), 40)
+	assert.Nil(t, dec.Decode(&buf))
+
+	enc.Reset()
+	assert.Nil(t, enc.Encode(&buf))
+	assert.Equal(t, full, buf.Len())
+	assert.Nil(t, dec.Decode(&buf))
+	assert.Equal(t, 100, dec.Len())
+	assert.Equal(t, 100, count(&dst))
+	assert.Equal(t, sourceState(&src, dec, posID), worldState(&dst, func(e ecs.Entity) ecs.Entity { return e }, posID))
+}
+
+func TestReplicateResync(t *testing.T) {
+	src := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&src)
+	enc := replicate.NewEncoder(&src, posID)
+	buf := bytes.Buffer{}
+
+	src.Batch().New(10, posID)
+	assert.Nil(t, enc.Encode(&buf))
+	buf.Reset()
+	src.Batch().New(10, posID)
+	assert.Nil(t, enc.Encode(&buf))
+
+	dst := ecs.NewWorld()
+	ecs.ComponentID[Position](&dst)
+	dec := replicate.NewDecoder(&dst)
+	assert.Equal(t, "delta without a preceding full snapshot", dec.Decode(&buf).Error())
+
+	enc.Reset()
+	assert.Nil(t, enc.Encode(&buf))
+	assert.Nil(t, dec.Decode(&buf))
+	assert.Equal(t, 20, dec.Len())
+	assert.Equal(t, 20, count(&dst))
+}
+
+func TestReplicatePipe(t *testing.T) {
+	src := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&src)
+	velID := ecs.ComponentID[Velocity](&src)
+
+	dst := ecs.NewWorld()
+	ecs.ComponentID[Velocity](&dst)
+	dstPosID := ecs.ComponentID[Position](&dst)
+
+	enc := replicate.NewEncoder(&src, posID)
+	dec := replicate.NewDecoder(&dst)
+
+	sender, receiver := net.Pipe()
+	steps := 10
+	done := make(chan error)
+	go func() {
+		e := src.NewEntity(posID, velID)
+		for i := 0; i < steps; i++ {
+			(*Position)(src.Get(e, posID)).X = float64(i)
+			if err := enc.Encode(sender); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- sender.Close()
+	}()
+
+	for i := 0; i < steps; i++ {
+		assert.Nil(t, dec.Decode(receiver))
+		query := dst.Query(ecs.All(dstPosID))
+		assert.True(t, query.Next())
+		assert.Equal(t, Position{X: float64(i)}, *(*Position)(query.Get(dstPosID)))
+		query.Close()
+	}
+	assert.Nil(t, <-done)
+}
+
+func TestReplicateErrors(t *testing.T) {
+	src := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&src)
+	labelID := ecs.ComponentID[Label](&src)
+	src.NewEntity(posID)
+
+	assert.PanicsWithValue(t, "component type replicate_test.Label contains pointers and can't be replicated",
+		func() { replicate.NewEncoder(&src, labelID) })
+
+	enc := replicate.NewEncoder(&src, posID)
+	buf := bytes.Buffer{}
+	assert.Nil(t, enc.Encode(&buf))
+	data := buf.Bytes()
+
+	dst := ecs.NewWorld()
+	dec := replicate.NewDecoder(&dst)
+	assert.Equal(t, "reading frame: unexpected EOF", dec.Decode(bytes.NewReader(data[:len(data)-1])).Error())
+
+	length, n := binary.Uvarint(data)
+	truncated := binary.AppendUvarint(nil, length-1)
+	truncated = append(truncated, data[n:len(data)-1]...)
+	assert.Equal(t, "malformed delta", dec.Decode(bytes.NewReader(truncated)).Error())
+
+	version := append([]byte{}, data...)
+	version[n] = 99
+	assert.Equal(t, "unsupported delta format version 99", dec.Decode(bytes.NewReader(version)).Error())
+
+	type Position struct {
+		X, Y float32
+	}
+	ecs.ComponentID[Position](&dst)
+	assert.Equal(t, "component type github.com/mlange-42/arche/replicate_test.Position has size 16 in the source, but 8 in the replica",
+		dec.Decode(bytes.NewReader(data)).Error())
+
+	dst = ecs.NewWorld()
+	dec = replicate.NewDecoder(&dst)
+	assert.Nil(t, dec.Decode(bytes.NewReader(data)))
+	assert.Equal(t, 1, count(&dst))
+	assert.Equal(t, 1, dec.Len())
+
+	assert.Equal(t, "EOF", dec.Decode(&bytes.Buffer{}).Error())
+}
+
+func count(w *ecs.World) int {
+	query := w.Query(ecs.All())
+	defer query.Close()
+	return query.Count()
+}
+
+func must(e ecs.Entity, ok bool) ecs.Entity {
+	if !ok {
+		panic("unknown entity")
+	}
+	return e
+}
+
+func ExampleEncoder() {
+	// The simulation world.
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	entity := world.NewEntity(posID)
+
+	// The replica world, e.g. in a visualization process.
+	replica := ecs.NewWorld()
+	replicaPosID := ecs.ComponentID[Position](&replica)
+
+	encoder := replicate.NewEncoder(&world, posID)
+	decoder := replicate.NewDecoder(&replica)
+	stream := bytes.Buffer{}
+
+	for step := 0; step < 3; step++ {
+		(*Position)(world.Get(entity, posID)).X = float64(step)
+
+		if err := encoder.Encode(&stream); err != nil {
+			panic(err)
+		}
+		if err := decoder.Decode(&stream); err != nil {
+			panic(err)
+		}
+	}
+
+	replicaEntity, _ := decoder.Entity(entity)
+	fmt.Println(*(*Position)(replica.Get(replicaEntity, replicaPosID)))
+	// Output: {2 0}
+}